@@ -13,7 +13,7 @@ import (
 	"github.com/B33Boy/Judgement/internal/server"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(apiServer, adminServer *http.Server, stopJanitor context.CancelFunc, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -23,6 +23,7 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 
 	log.Println("shutting down gracefully, press Ctrl+C again to force")
 	stop() // Allow Ctrl+C to force shutdown
+	stopJanitor()
 
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
@@ -31,6 +32,11 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	if err := apiServer.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown with error: %v", err)
 	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Printf("Admin server forced to shutdown with error: %v", err)
+		}
+	}
 
 	log.Println("Server exiting")
 
@@ -40,14 +46,31 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 
 func main() {
 
+	janitorConfig := app.LoadJanitorConfig()
+
 	app := app.NewApp()
+	adminServer := server.NewAdminServer(app)
 	server := server.NewServer(app)
 
+	// Clean up abandoned sessions in the background
+	janitorCtx, stopJanitor := context.WithCancel(context.Background())
+	app.StartJanitor(janitorCtx, janitorConfig)
+
+	// Metrics stay off the public listener
+	if adminServer != nil {
+		go func() {
+			err := adminServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				log.Printf("admin server error: %s", err)
+			}
+		}()
+	}
+
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, adminServer, stopJanitor, done)
 
 	err := server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
@@ -0,0 +1,158 @@
+package main
+
+// Generates the frontend protocol types and a JSON Schema from the message registry
+//
+//	go run ./cmd/protogen
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/B33Boy/Judgement/internal/app"
+)
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	rawMessageType  = reflect.TypeFor[json.RawMessage]()
+	textMarshalType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func main() {
+	tsPath := flag.String("ts", "frontend/src/generated/protocol.ts", "TypeScript output file")
+	schemaPath := flag.String("schema", "frontend/src/generated/protocol.schema.json", "JSON Schema output file")
+	flag.Parse()
+
+	messages := app.Messages()
+	types := collectTypes(messages)
+
+	schema, err := json.MarshalIndent(buildSchema(messages, types), "", "  ")
+	if err != nil {
+		log.Fatalf("failed to encode schema: %v", err)
+	}
+
+	if err := writeFile(*tsPath, []byte(generateTypeScript(messages, types))); err != nil {
+		log.Fatal(err)
+	}
+	if err := writeFile(*schemaPath, append(schema, '\n')); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	log.Println("wrote", path)
+	return nil
+}
+
+// ================= Type walking =================
+
+type field struct {
+	Name     string
+	Type     reflect.Type
+	Optional bool // omitempty, may be left out
+}
+
+// Named types reachable from the registered payloads, sorted by name
+func collectTypes(messages []app.MessageSpec) []reflect.Type {
+	seen := make(map[string]reflect.Type)
+
+	var walk func(typ reflect.Type)
+	walk = func(typ reflect.Type) {
+		if typ == nil || isOpaque(typ) {
+			return
+		}
+		if isNamed(typ) {
+			if prev, ok := seen[typ.Name()]; ok {
+				if prev != typ {
+					log.Fatalf("type name %s used by both %s and %s", typ.Name(), prev.PkgPath(), typ.PkgPath())
+				}
+				return
+			}
+			seen[typ.Name()] = typ
+		}
+		if isText(typ) {
+			return
+		}
+
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			walk(typ.Elem())
+		case reflect.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+		case reflect.Struct:
+			for _, f := range fields(typ) {
+				walk(f.Type)
+			}
+		}
+	}
+
+	for _, msg := range messages {
+		walk(msg.Payload)
+	}
+
+	types := make([]reflect.Type, 0, len(seen))
+	for _, typ := range seen {
+		types = append(types, typ)
+	}
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return types
+}
+
+// Named types get their own definition, builtins and composites are inlined
+func isNamed(typ reflect.Type) bool {
+	return typ.Name() != "" && typ.PkgPath() != ""
+}
+
+// Types that encode themselves and are always inlined
+func isOpaque(typ reflect.Type) bool {
+	return typ == timeType || typ == rawMessageType
+}
+
+// Named types sent as strings, e.g. cards
+func isText(typ reflect.Type) bool {
+	return isNamed(typ) && typ.Implements(textMarshalType)
+}
+
+// Exported fields as encoding/json sees them
+func fields(typ reflect.Type) []field {
+	var out []field
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+
+		out = append(out, field{
+			Name:     name,
+			Type:     sf.Type,
+			Optional: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	return out
+}
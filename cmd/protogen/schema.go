@@ -0,0 +1,134 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/B33Boy/Judgement/internal/app"
+)
+
+type jsonObject = map[string]any
+
+func buildSchema(messages []app.MessageSpec, types []reflect.Type) jsonObject {
+	defs := make(jsonObject, len(types)+len(messages))
+	for _, typ := range types {
+		defs[typ.Name()] = schemaDefinition(typ)
+	}
+
+	var inbound, outbound []any
+	for _, msg := range messages {
+		name := "message_" + string(msg.Type)
+		defs[name] = messageSchema(msg)
+
+		ref := jsonObject{"$ref": "#/$defs/" + name}
+		if msg.Direction == app.Inbound {
+			inbound = append(inbound, ref)
+		} else {
+			outbound = append(outbound, ref)
+		}
+	}
+	defs["InboundMessage"] = jsonObject{"oneOf": inbound}
+	defs["OutboundMessage"] = jsonObject{"oneOf": outbound}
+
+	return jsonObject{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Judgement websocket protocol (" + app.Protocol{Version: app.CurrentProtocol, Encoding: "json"}.Name() + ")",
+		"oneOf": []any{
+			jsonObject{"$ref": "#/$defs/InboundMessage"},
+			jsonObject{"$ref": "#/$defs/OutboundMessage"},
+		},
+		"$defs": defs,
+	}
+}
+
+func messageSchema(msg app.MessageSpec) jsonObject {
+	props := jsonObject{
+		"id":   jsonObject{"type": "string"},
+		"type": jsonObject{"const": msg.Type},
+	}
+	if msg.Direction == app.Outbound {
+		props["seq"] = jsonObject{"type": "integer", "minimum": 0}
+	}
+
+	required := []string{"type"}
+	if msg.Payload != nil {
+		props["payload"] = schemaRef(msg.Payload, false)
+		if !msg.Optional {
+			required = append(required, "payload")
+		}
+	}
+
+	return jsonObject{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func schemaDefinition(typ reflect.Type) jsonObject {
+	if values := app.EnumValues(typ); values != nil {
+		return jsonObject{"enum": values}
+	}
+	if isText(typ) {
+		return jsonObject{"type": "string"}
+	}
+	return schemaInline(typ)
+}
+
+func schemaRef(typ reflect.Type, optional bool) jsonObject {
+	if isNamed(typ) && !isOpaque(typ) {
+		return jsonObject{"$ref": "#/$defs/" + typ.Name()}
+	}
+	if typ.Kind() == reflect.Pointer && !optional {
+		return jsonObject{"anyOf": []any{schemaRef(typ.Elem(), false), jsonObject{"type": "null"}}}
+	}
+	return schemaInline(typ)
+}
+
+func schemaInline(typ reflect.Type) jsonObject {
+	switch {
+	case typ == timeType:
+		return jsonObject{"type": "string", "format": "date-time"}
+	case typ == rawMessageType:
+		return jsonObject{}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return jsonObject{"type": "boolean"}
+	case reflect.String:
+		return jsonObject{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return jsonObject{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonObject{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return jsonObject{"type": "number"}
+	case reflect.Pointer:
+		return schemaRef(typ.Elem(), true)
+	case reflect.Slice, reflect.Array:
+		// nil slices encode as null
+		return jsonObject{"type": []string{"array", "null"}, "items": schemaRef(typ.Elem(), false)}
+	case reflect.Map:
+		return jsonObject{"type": "object", "additionalProperties": schemaRef(typ.Elem(), false)}
+	case reflect.Struct:
+		props := jsonObject{}
+		var required []string
+		for _, f := range fields(typ) {
+			props[f.Name] = schemaRef(f.Type, f.Optional)
+			if !f.Optional {
+				required = append(required, f.Name)
+			}
+		}
+		schema := jsonObject{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return jsonObject{}
+}
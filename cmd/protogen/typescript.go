@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/B33Boy/Judgement/internal/app"
+)
+
+func generateTypeScript(messages []app.MessageSpec, types []reflect.Type) string {
+	var b strings.Builder
+
+	b.WriteString("// Code generated by cmd/protogen. DO NOT EDIT.\n")
+
+	current := app.Protocol{Version: app.CurrentProtocol, Encoding: "json"}
+	fmt.Fprintf(&b, "\nexport const PROTOCOL_VERSION = %d;\n", current.Version)
+	fmt.Fprintf(&b, "export const SUBPROTOCOL = %q;\n", current.Name())
+
+	for _, typ := range types {
+		b.WriteString("\n")
+		if typ.Kind() == reflect.Struct && !isText(typ) {
+			fmt.Fprintf(&b, "export interface %s {\n", typ.Name())
+			for _, f := range fields(typ) {
+				opt := ""
+				if f.Optional {
+					opt = "?"
+				}
+				fmt.Fprintf(&b, "  %s%s: %s;\n", f.Name, opt, tsType(f.Type, f.Optional))
+			}
+			b.WriteString("}\n")
+			continue
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n", typ.Name(), tsDefinition(typ))
+	}
+
+	var inbound, outbound []string
+	for _, msg := range messages {
+		name := fmt.Sprintf("%q", msg.Type)
+		if msg.Direction == app.Inbound {
+			inbound = append(inbound, name)
+		} else {
+			outbound = append(outbound, name)
+		}
+	}
+
+	b.WriteString("\n// FE -> BE\n")
+	fmt.Fprintf(&b, "export type InboundMessageType =\n  | %s;\n", strings.Join(inbound, "\n  | "))
+	b.WriteString("\n// BE -> FE\n")
+	fmt.Fprintf(&b, "export type OutboundMessageType =\n  | %s;\n", strings.Join(outbound, "\n  | "))
+	b.WriteString("\nexport type MessageType = InboundMessageType | OutboundMessageType;\n")
+
+	b.WriteString("\nexport interface Payloads {\n")
+	for _, msg := range messages {
+		payload := "undefined"
+		if msg.Payload != nil {
+			payload = tsType(msg.Payload, false)
+			if msg.Optional {
+				payload += " | undefined"
+			}
+		}
+		fmt.Fprintf(&b, "  %s: %s;\n", msg.Type, payload)
+	}
+	b.WriteString("}\n")
+
+	b.WriteString(`
+type WithPayload<K extends MessageType> = undefined extends Payloads[K]
+  ? { payload?: Payloads[K] }
+  : { payload: Payloads[K] };
+
+export type InboundMessage = {
+  [K in InboundMessageType]: { id?: string; type: K } & WithPayload<K>;
+}[InboundMessageType];
+
+export type OutboundMessage = {
+  [K in OutboundMessageType]: { id?: string; seq?: number; type: K } & WithPayload<K>;
+}[OutboundMessageType];
+`)
+	return b.String()
+}
+
+// Right hand side of a named type declaration
+func tsDefinition(typ reflect.Type) string {
+	if values := app.EnumValues(typ); values != nil {
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literal, _ := json.Marshal(v)
+			literals[i] = string(literal)
+		}
+		return strings.Join(literals, " | ")
+	}
+	if isText(typ) {
+		return "string"
+	}
+	return tsInline(typ)
+}
+
+// Reference to a type from a field or payload
+func tsType(typ reflect.Type, optional bool) string {
+	if isNamed(typ) && !isOpaque(typ) {
+		return typ.Name()
+	}
+	if typ.Kind() == reflect.Pointer && !optional {
+		return tsType(typ.Elem(), false) + " | null"
+	}
+	return tsInline(typ)
+}
+
+func tsInline(typ reflect.Type) string {
+	switch {
+	case typ == timeType:
+		return "string" // RFC 3339
+	case typ == rawMessageType:
+		return "unknown"
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Pointer:
+		return tsType(typ.Elem(), true)
+	case reflect.Slice, reflect.Array:
+		elem := tsType(typ.Elem(), false)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return fmt.Sprintf("Record<%s, %s>", tsType(typ.Key(), false), tsType(typ.Elem(), false))
+	case reflect.Struct:
+		var b strings.Builder
+		b.WriteString("{ ")
+		for _, f := range fields(typ) {
+			opt := ""
+			if f.Optional {
+				opt = "?"
+			}
+			fmt.Fprintf(&b, "%s%s: %s; ", f.Name, opt, tsType(f.Type, f.Optional))
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return "unknown"
+}
@@ -1,7 +1,5 @@
 import { useDroppable } from "@dnd-kit/core";
 import {
-  rankFromValue,
-  suitFromValue,
   type Card,
   type GameState,
   type PlayerPublic,
@@ -57,7 +55,7 @@ function TableEntry({ player, isCurrentTurn, card }: TableEntryProps) {
     >
       <p className="player-name">{player.name}</p>
       <div className="table-entry">
-        {card && <CardImg name={cardToImgName(card)} />}
+        {card && <CardImg name={card} />}
       </div>
     </div>
   );
@@ -68,14 +66,3 @@ function TableEntry({ player, isCurrentTurn, card }: TableEntryProps) {
     content
   );
 }
-
-export function cardToImgName(card: Card): string {
-  const suit = suitFromValue[card.suit];
-  const rank = rankFromValue[card.rank];
-
-  if (!suit || !rank) {
-    throw new Error(`Invalid card: ${JSON.stringify(card)}`);
-  }
-
-  return `${suit}-${rank}`;
-}
@@ -8,6 +8,7 @@ import {
 } from "react";
 import { useNavigate } from "react-router-dom";
 import type { WSEnvelope, GameState, Players } from "../types";
+import { SUBPROTOCOL, type Rules } from "../generated/protocol";
 
 const WS_BASE = `ws://localhost:${import.meta.env.VITE_PORT}`;
 
@@ -17,6 +18,7 @@ interface GameContextType {
   isConnected: boolean;
   playerId: string | null;
   players: Players;
+  rules: Rules | null;
   hand: string[];
   gameState: GameState | null;
   connect: (sessionId: string, playerName: string) => void;
@@ -31,6 +33,7 @@ export function GameProvider({ children }: { children: ReactNode }) {
   const [isConnected, setIsConnected] = useState(false);
   const [playerId, setPlayerId] = useState<string | null>(null);
   const [players, setPlayers] = useState<Players>([]);
+  const [rules, setRules] = useState<Rules | null>(null);
   const [hand, setHand] = useState<string[]>([]);
   // const [roundInfo, setRoundInfo] = useState<RoundInfo | null>(null);
   // const [scores, setScores] = useState<Scores | null>(null);
@@ -52,10 +55,13 @@ export function GameProvider({ children }: { children: ReactNode }) {
       // Create new ws conn
       const ws = new WebSocket(
         `${WS_BASE}/ws?sessionId=${sessionId}&playerName=${playerName}`,
+        [SUBPROTOCOL],
       );
 
       ws.onopen = () => setIsConnected(true);
-      ws.onclose = () => {
+      ws.onclose = (e) => {
+        // Policy closes carry a reason worth showing, e.g. kicked or unsupported version
+        if (e.code === 1008) alert(e.reason);
         setIsConnected(false);
         wsRef.current = null;
       };
@@ -66,13 +72,17 @@ export function GameProvider({ children }: { children: ReactNode }) {
         switch (msg.type) {
           // Lobby
           case "welcome":
-            setPlayerId(msg.payload);
+            setPlayerId(msg.payload.playerId);
             break;
 
           case "players_update":
             setPlayers(msg.payload ?? []);
             break;
 
+          case "rules_update":
+            setRules(msg.payload);
+            break;
+
           case "game_started":
             navigate(`/game/${sessionId}/${currentPlayerRef.current}`);
             break;
@@ -83,11 +93,84 @@ export function GameProvider({ children }: { children: ReactNode }) {
             setHand(msg.payload.cards ?? []);
             break;
 
+          // Full snapshot on join or resync
           case "state_sync":
             console.log("State Sync: ", msg.payload);
             setGameState(msg.payload);
             break;
 
+          // Incremental updates on top of the last snapshot
+          case "round_started":
+            setGameState((prev) =>
+              prev && {
+                ...prev,
+                round: msg.payload.round,
+                dealer: msg.payload.dealer,
+                trumpSuit: null,
+                table: {},
+                bids: {},
+                handsWon: {},
+              },
+            );
+            break;
+
+          case "bid_placed":
+            setGameState((prev) =>
+              prev && {
+                ...prev,
+                bids: { ...prev.bids, [msg.payload.player]: msg.payload.bid },
+              },
+            );
+            break;
+
+          case "card_played":
+            setGameState((prev) =>
+              prev && {
+                ...prev,
+                trumpSuit: msg.payload.trumpSuit,
+                table: { ...prev.table, [msg.payload.player]: msg.payload.card },
+              },
+            );
+            break;
+
+          case "trick_won":
+            setGameState((prev) =>
+              prev && {
+                ...prev,
+                table: {},
+                handsWon: {
+                  ...prev.handsWon,
+                  [msg.payload.winner]: msg.payload.handsWon,
+                },
+              },
+            );
+            break;
+
+          case "round_scored":
+            setGameState((prev) => {
+              if (!prev) return prev;
+              const scores = { ...prev.scores };
+              for (const [id, score] of Object.entries(msg.payload.scores)) {
+                scores[id] = [...(scores[id] ?? []), score as number];
+              }
+              return { ...prev, scores };
+            });
+            break;
+
+          case "turn_changed":
+            setGameState((prev) =>
+              prev && {
+                ...prev,
+                turnPlayer: msg.payload.player,
+                state: msg.payload.state,
+                paused: msg.payload.paused,
+                deadline: msg.payload.deadline,
+                legalCards: msg.payload.legalCards,
+                legalBids: msg.payload.legalBids,
+              },
+            );
+            break;
+
           case "error":
             alert(msg.payload.message);
             break;
@@ -116,6 +199,7 @@ export function GameProvider({ children }: { children: ReactNode }) {
         isConnected,
         playerId,
         players,
+        rules,
         hand,
         gameState,
         connect,
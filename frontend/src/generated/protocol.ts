@@ -0,0 +1,408 @@
+// Code generated by cmd/protogen. DO NOT EDIT.
+
+export const PROTOCOL_VERSION = 3;
+export const SUBPROTOCOL = "judgement.v3+json";
+
+export interface AbandonVote {
+  abandon: boolean;
+}
+
+export interface AbandonVoteStatus {
+  votes: PlayerID[];
+  needed: number;
+}
+
+export interface Ack {
+  id: string;
+}
+
+export type Bid = number;
+
+export interface BidAdvice {
+  expectedTricks: number;
+  chances: number[];
+  suggestedBid: Bid;
+  samples: number;
+}
+
+export interface BidPlaced {
+  player: PlayerID;
+  bid: Bid;
+}
+
+export type Card = string;
+
+export interface CardPlayed {
+  player: PlayerID;
+  card: Card;
+  trumpSuit: Suit | null;
+}
+
+export interface ChatMessage {
+  from: PlayerID;
+  name: string;
+  text: string;
+  to?: PlayerID;
+  timestamp: number;
+}
+
+export interface ChatSend {
+  text: string;
+  to?: PlayerID;
+}
+
+export interface Claim {
+  tricks: number;
+}
+
+export interface ClaimMade {
+  player: PlayerID;
+  tricks: number;
+  deadline: string;
+}
+
+export interface ClaimResolved {
+  player: PlayerID;
+  tricks: number;
+  accepted: boolean;
+  verified: boolean;
+  reason?: string;
+}
+
+export interface ClaimResponse {
+  accept: boolean;
+}
+
+export type Emote = "nice_trick" | "ouch" | "👍" | "well_bid" | "hurry_up" | "😂";
+
+export interface EmoteReaction {
+  from: PlayerID;
+  emote: Emote;
+  target?: PlayerID;
+  timestamp: number;
+}
+
+export interface EmoteSend {
+  emote: Emote;
+  target?: PlayerID;
+}
+
+export type ErrorCode = "invalid_payload" | "unknown_message" | "not_allowed" | "not_host" | "not_found" | "rate_limited" | "muted" | "no_game" | "game_paused" | "wrong_phase" | "not_seated" | "not_your_turn" | "must_follow_suit" | "card_not_in_hand" | "bid_out_of_range";
+
+export interface ErrorPayload {
+  id?: string;
+  code: ErrorCode;
+  message: string;
+}
+
+export interface GameEndPayload {
+  gameId: string;
+  reason: GameEndReason;
+  roundsPlayed: number;
+  scores: Record<PlayerID, Score>;
+}
+
+export type GameEndReason = "completed" | "abandoned";
+
+export type Hand = Card[];
+
+export interface HandOrder {
+  trumpFirst: boolean;
+  alternateColours: boolean;
+  highFirst: boolean;
+}
+
+export interface MakeBid {
+  bid: Bid;
+}
+
+export interface MutePlayer {
+  playerId: PlayerID;
+  muted: boolean;
+}
+
+export interface Play {
+  player: PlayerID;
+  card: Card;
+  claimed?: boolean;
+}
+
+export interface PlayerHand {
+  cards: Hand;
+}
+
+export type PlayerID = string;
+
+export interface PlayerPublic {
+  id: PlayerID;
+  name: string;
+  isHost: boolean;
+  ready: boolean;
+  isBot?: boolean;
+}
+
+export interface PlayerView {
+  round: Round;
+  maxRounds: Round;
+  state: State;
+  dealer: PlayerID;
+  turnPlayer: PlayerID;
+  trumpSuit: Suit | null;
+  seats: SeatView[];
+  table: Record<PlayerID, Card>;
+  bids: Record<PlayerID, Bid>;
+  handsWon: Record<PlayerID, number>;
+  scores: Record<PlayerID, Score[]>;
+  paused: boolean;
+  deadline?: string;
+  lastTrick?: Trick;
+  hand?: Hand;
+  legalCards?: Card[];
+  legalBids?: Bid[];
+  bidAdvice?: BidAdvice;
+}
+
+export type ProtocolVersion = number;
+
+export interface Rematch {
+  rotateDealer: boolean;
+}
+
+export interface Resume {
+  lastSeq: number;
+}
+
+export type Round = number;
+
+export interface RoundScored {
+  round: Round;
+  bids: Record<PlayerID, Bid>;
+  handsWon: Record<PlayerID, number>;
+  scores: Record<PlayerID, Score>;
+  totals: Record<PlayerID, Score>;
+}
+
+export interface RoundSolved {
+  round: Round;
+  doubleDummy: Record<PlayerID, number>;
+}
+
+export interface RoundStarted {
+  round: Round;
+  dealer: PlayerID;
+  cardsPerPlayer: number;
+}
+
+export interface Rules {
+  maxRounds: Round;
+  cardsPerRound: number;
+  minPlayers: number;
+  maxPlayers: number;
+  turnSeconds: number;
+  handOrder: HandOrder;
+  coach: boolean;
+}
+
+export type Score = number;
+
+export interface SeatClaimed {
+  seatId: PlayerID;
+  playerName: string;
+  previousId: PlayerID;
+}
+
+export interface SeatView {
+  id: PlayerID;
+  name: string;
+  cardCount: number;
+}
+
+export interface Series {
+  games: number;
+  totals: Record<PlayerID, Score>;
+  wins: Record<PlayerID, number>;
+}
+
+export interface SetReady {
+  ready: boolean;
+}
+
+export type State = "bidding" | "playing" | "resolution" | "gameover";
+
+export type Suit = "SPADE" | "HEART" | "DIAMOND" | "CLUB";
+
+export interface TargetPlayer {
+  playerId: PlayerID;
+}
+
+export interface Trick {
+  leader: PlayerID;
+  plays: Play[];
+  winner: PlayerID;
+}
+
+export interface TrickHistory {
+  round: Round;
+  tricks: Trick[];
+}
+
+export interface TrickHistoryRequest {
+  round?: Round;
+}
+
+export interface TrickWon {
+  winner: PlayerID;
+  handsWon: number;
+}
+
+export interface TurnChanged {
+  player: PlayerID;
+  state: State;
+  deadline?: string;
+  paused: boolean;
+  legalCards?: Card[];
+  legalBids?: Bid[];
+}
+
+export interface UndoRequested {
+  player: PlayerID;
+  deadline: string;
+}
+
+export interface UndoResolved {
+  player: PlayerID;
+  approved: boolean;
+  reason?: string;
+}
+
+export interface UndoResponse {
+  approve: boolean;
+}
+
+export interface Welcome {
+  playerId: PlayerID;
+  protocolVersion: ProtocolVersion;
+}
+
+// FE -> BE
+export type InboundMessageType =
+  | "abandon_game"
+  | "add_bot"
+  | "chat_send"
+  | "claim"
+  | "claim_response"
+  | "claim_seat"
+  | "configure_game"
+  | "emote_send"
+  | "get_trick_history"
+  | "kick_player"
+  | "make_bid"
+  | "mute_player"
+  | "pause_game"
+  | "play_card"
+  | "rematch"
+  | "resume"
+  | "resume_game"
+  | "set_ready"
+  | "start_game"
+  | "transfer_host"
+  | "undo_request"
+  | "undo_response";
+
+// BE -> FE
+export type OutboundMessageType =
+  | "abandon_vote"
+  | "ack"
+  | "bid_advice"
+  | "bid_placed"
+  | "card_played"
+  | "chat_history"
+  | "chat_message"
+  | "claim_made"
+  | "claim_resolved"
+  | "emote"
+  | "error"
+  | "game_end"
+  | "game_started"
+  | "player_hand"
+  | "players_update"
+  | "round_scored"
+  | "round_solved"
+  | "round_started"
+  | "rules_update"
+  | "seat_claimed"
+  | "series_update"
+  | "state_sync"
+  | "trick_history"
+  | "trick_won"
+  | "turn_changed"
+  | "undo_requested"
+  | "undo_resolved"
+  | "welcome";
+
+export type MessageType = InboundMessageType | OutboundMessageType;
+
+export interface Payloads {
+  abandon_game: AbandonVote | undefined;
+  add_bot: undefined;
+  chat_send: ChatSend;
+  claim: Claim;
+  claim_response: ClaimResponse;
+  claim_seat: TargetPlayer;
+  configure_game: Rules;
+  emote_send: EmoteSend;
+  get_trick_history: TrickHistoryRequest | undefined;
+  kick_player: TargetPlayer;
+  make_bid: MakeBid;
+  mute_player: MutePlayer;
+  pause_game: undefined;
+  play_card: Card;
+  rematch: Rematch | undefined;
+  resume: Resume;
+  resume_game: undefined;
+  set_ready: SetReady;
+  start_game: undefined;
+  transfer_host: TargetPlayer;
+  undo_request: undefined;
+  undo_response: UndoResponse;
+  abandon_vote: AbandonVoteStatus;
+  ack: Ack;
+  bid_advice: BidAdvice;
+  bid_placed: BidPlaced;
+  card_played: CardPlayed;
+  chat_history: ChatMessage[];
+  chat_message: ChatMessage;
+  claim_made: ClaimMade;
+  claim_resolved: ClaimResolved;
+  emote: EmoteReaction;
+  error: ErrorPayload;
+  game_end: GameEndPayload;
+  game_started: undefined;
+  player_hand: PlayerHand;
+  players_update: PlayerPublic[];
+  round_scored: RoundScored;
+  round_solved: RoundSolved;
+  round_started: RoundStarted;
+  rules_update: Rules;
+  seat_claimed: SeatClaimed;
+  series_update: Series;
+  state_sync: PlayerView;
+  trick_history: TrickHistory;
+  trick_won: TrickWon;
+  turn_changed: TurnChanged;
+  undo_requested: UndoRequested;
+  undo_resolved: UndoResolved;
+  welcome: Welcome;
+}
+
+type WithPayload<K extends MessageType> = undefined extends Payloads[K]
+  ? { payload?: Payloads[K] }
+  : { payload: Payloads[K] };
+
+export type InboundMessage = {
+  [K in InboundMessageType]: { id?: string; type: K } & WithPayload<K>;
+}[InboundMessageType];
+
+export type OutboundMessage = {
+  [K in OutboundMessageType]: { id?: string; seq?: number; type: K } & WithPayload<K>;
+}[OutboundMessageType];
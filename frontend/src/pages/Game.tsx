@@ -11,8 +11,6 @@ import PlayerHand from "../components/PlayerHand";
 
 import { DndContext, type DragEndEvent } from "@dnd-kit/core";
 
-import { suitMap, rankMap } from "../types.ts";
-
 export default function GamePage() {
   const { sessionId, playerName } = useParams();
   const {
@@ -54,10 +52,9 @@ export default function GamePage() {
     if (targetPlayerId != playerId) return;
 
     const card = String(active.id).replace("card-", ""); // e.g. "card-CLUB-2"
-    const [suit, rank] = card.split("-");
 
     console.log(`${playerName} played ${card}`);
-    sendMessage("play_card", { suit: suitMap[suit], rank: rankMap[rank] });
+    sendMessage("play_card", card);
   }
 
   return (
@@ -6,7 +6,7 @@ import type { PlayerPublic } from "../types";
 
 export default function SessionPage() {
   const { sessionId } = useParams();
-  const { connect, players, sendMessage } = useGame();
+  const { connect, players, rules, playerId, sendMessage } = useGame();
   const playerName = getPlayerName();
 
   // If no ID or name, go home
@@ -20,6 +20,16 @@ export default function SessionPage() {
     sendMessage("start_game");
   };
 
+  const me = players.find((p: PlayerPublic) => p.id === playerId);
+  const handleToggleReady = () => {
+    sendMessage("set_ready", { ready: !me?.ready });
+  };
+
+  // Server checks these too, the buttons only mirror the session rules
+  const minPlayers = rules?.minPlayers ?? 2;
+  const maxPlayers = rules?.maxPlayers ?? 7;
+  const everyoneReady = players.every((p: PlayerPublic) => p.ready);
+
   return (
     <div>
       <h2>Session: {sessionId}</h2>
@@ -29,17 +39,34 @@ export default function SessionPage() {
         <h4>Players ({players.length})</h4>
         <ul>
           {players.map((p: PlayerPublic) => (
-            <li key={p.id}>{p.name}</li>
+            <li key={p.id}>
+              {p.name}
+              {p.isBot && " (bot)"}
+              {p.ready ? " (ready)" : " (not ready)"}
+            </li>
           ))}
         </ul>
       </div>
 
+      <button disabled={!me} onClick={handleToggleReady}>
+        {me?.ready ? "Not Ready" : "Ready"}
+      </button>
       <button
-        disabled={players.length < 3 || players.length > 7}
+        disabled={
+          players.length < minPlayers ||
+          players.length > maxPlayers ||
+          !everyoneReady
+        }
         onClick={handleStartGame}
       >
         Start Game
       </button>
+      <button
+        disabled={players.length >= maxPlayers}
+        onClick={() => sendMessage("add_bot")}
+      >
+        Add Bot
+      </button>
     </div>
   );
 }
@@ -4,8 +4,11 @@ go 1.25.5
 
 require (
 	github.com/coder/websocket v1.8.14
+	github.com/fxamacker/cbor/v2 v2.9.2
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-chi/cors v1.2.2
 	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
 )
+
+require github.com/x448/float16 v0.8.4 // indirect
@@ -2,6 +2,7 @@ package app
 
 type App struct {
 	sessionStore *SessionStore
+	invites      *InviteSigner
 }
 
 func NewApp() *App {
@@ -9,5 +10,6 @@ func NewApp() *App {
 	sessionStore := NewSessionStore()
 	return &App{
 		sessionStore: sessionStore,
+		invites:      NewInviteSignerFromEnv(),
 	}
 }
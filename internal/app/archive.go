@@ -0,0 +1,181 @@
+package app
+
+// Finished games kept in memory for review after their session is gone
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const (
+	maxArchivedGames  = 1000
+	archiveSolveLimit = 2_000_000 // per player, for rounds too large to solve during the game
+	maxRunningReviews = 2
+)
+
+type GameArchive struct {
+	games   map[string]g.GameRecord
+	reviews map[string]*reviewJob
+	solves  map[string]*solveJob
+	order   []string // oldest first
+	limit   int
+	slots   chan struct{} // bounds the reviews and solves running at once
+	mu      sync.RWMutex
+}
+
+// Review of an archived game, worked out in the background
+type reviewJob struct {
+	done   chan struct{}
+	review g.GameReview
+}
+
+// Rounds of an archived game solved in the background, once. Rounds that
+// hit the limit stay unsolved rather than being searched again per request.
+type solveJob struct {
+	done     chan struct{}
+	analysis []RoundAnalysis
+}
+
+// Double-dummy result of a round next to what was actually bid and taken
+type RoundAnalysis struct {
+	Round       g.Round              `json:"round"`
+	Bids        map[t.PlayerID]g.Bid `json:"bids"`
+	HandsWon    map[t.PlayerID]int   `json:"handsWon"`
+	DoubleDummy map[t.PlayerID]int   `json:"doubleDummy"` // null if the deal is too large to solve
+}
+
+func NewGameArchive(limit int) *GameArchive {
+	return &GameArchive{
+		games:   make(map[string]g.GameRecord),
+		reviews: make(map[string]*reviewJob),
+		solves:  make(map[string]*solveJob),
+		limit:   limit,
+		slots:   make(chan struct{}, maxRunningReviews),
+	}
+}
+
+// Stores the record and starts reviewing it and solving what the game left
+// unsolved
+func (a *GameArchive) Add(record g.GameRecord) {
+	job := &reviewJob{done: make(chan struct{})}
+	solve := &solveJob{done: make(chan struct{})}
+
+	a.mu.Lock()
+	if _, exists := a.games[record.ID]; !exists {
+		a.order = append(a.order, record.ID)
+	}
+	a.games[record.ID] = record
+	a.reviews[record.ID] = job
+	a.solves[record.ID] = solve
+
+	for len(a.order) > a.limit {
+		delete(a.games, a.order[0])
+		delete(a.reviews, a.order[0])
+		delete(a.solves, a.order[0])
+		a.order = a.order[1:]
+	}
+	a.mu.Unlock()
+
+	go a.runReview(record, job)
+	go a.runSolve(record, solve)
+}
+
+func (a *GameArchive) runReview(record g.GameRecord, job *reviewJob) {
+	a.slots <- struct{}{}
+	defer func() { <-a.slots }()
+
+	start := time.Now()
+	job.review = g.ReviewGame(record)
+	close(job.done)
+	log.Printf("Game (%v) reviewed in %v", record.ID, time.Since(start))
+}
+
+// The review of a game once it is ready. ready is false while it is still
+// being worked out, exists is false for games that are not archived.
+func (a *GameArchive) Review(id string) (review g.GameReview, ready, exists bool) {
+	a.mu.RLock()
+	job, exists := a.reviews[id]
+	a.mu.RUnlock()
+	if !exists {
+		return review, false, false
+	}
+
+	select {
+	case <-job.done:
+		return job.review, true, true
+	default:
+		return review, false, true
+	}
+}
+
+func (a *GameArchive) Get(id string) (g.GameRecord, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	record, ok := a.games[id]
+	return record, ok
+}
+
+// Solves the rounds left unsolved during the game and keeps the results.
+// Records handed out earlier are never modified, the rounds are replaced.
+func (a *GameArchive) runSolve(record g.GameRecord, job *solveJob) {
+	a.slots <- struct{}{}
+	defer func() { <-a.slots }()
+
+	rounds := slices.Clone(record.Rounds)
+	solved := false
+	for i, round := range rounds {
+		if round.DoubleDummy != nil {
+			continue
+		}
+		dd, err := g.SolveDoubleDummy(round.Deal, archiveSolveLimit)
+		if err != nil {
+			log.Printf("Game (%v) round %d left unsolved: %v", record.ID, round.Round, err)
+			continue
+		}
+		rounds[i].DoubleDummy = dd
+		solved = true
+	}
+
+	if solved {
+		a.mu.Lock()
+		if current, ok := a.games[record.ID]; ok {
+			current.Rounds = rounds
+			a.games[record.ID] = current
+		}
+		a.mu.Unlock()
+	}
+
+	job.analysis = make([]RoundAnalysis, len(rounds))
+	for i, round := range rounds {
+		job.analysis[i] = RoundAnalysis{
+			Round:       round.Round,
+			Bids:        round.Bids,
+			HandsWon:    round.HandsWon,
+			DoubleDummy: round.DoubleDummy,
+		}
+	}
+	close(job.done)
+}
+
+// Double-dummy results of every round, including those too large to solve
+// while the game was running. ready and exists work as for Review.
+func (a *GameArchive) DoubleDummy(id string) (analysis []RoundAnalysis, ready, exists bool) {
+	a.mu.RLock()
+	job, exists := a.solves[id]
+	a.mu.RUnlock()
+	if !exists {
+		return nil, false, false
+	}
+
+	select {
+	case <-job.done:
+		return job.analysis, true, true
+	default:
+		return nil, false, true
+	}
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestGameArchiveLimit(t *testing.T) {
+	archive := NewGameArchive(2)
+	archive.Add(g.GameRecord{ID: "one"})
+	archive.Add(g.GameRecord{ID: "two"})
+	archive.Add(g.GameRecord{ID: "three"})
+
+	if _, ok := archive.Get("one"); ok {
+		t.Errorf("expected the oldest game to be dropped")
+	}
+	if _, ok := archive.Get("three"); !ok {
+		t.Errorf("expected the newest game to be kept")
+	}
+}
+
+func TestDoubleDummyHandler(t *testing.T) {
+	card := func(s string) g.Card {
+		c, _ := g.ParseCard(s)
+		return c
+	}
+
+	// Left unsolved as if it had been too large during the game
+	app := NewApp()
+	app.sessionStore.archive.Add(g.GameRecord{
+		ID: "game",
+		Rounds: []g.RoundRecord{{
+			Deal: g.Deal{
+				Seats:  []types.PlayerID{"a", "b"},
+				Hands:  map[types.PlayerID]g.Hand{"a": {card("AS"), card("KS")}, "b": {card("2H"), card("3H")}},
+				Leader: "a",
+			},
+		}},
+	})
+
+	server := httptest.NewServer(app.RegisterRoutes())
+	defer server.Close()
+
+	<-app.sessionStore.archive.solves["game"].done
+
+	resp, err := http.Get(server.URL + "/api/games/game/double-dummy")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var analysis []RoundAnalysis
+	if err := json.NewDecoder(resp.Body).Decode(&analysis); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if len(analysis) != 1 || analysis[0].DoubleDummy["a"] != 2 || analysis[0].DoubleDummy["b"] != 0 {
+		t.Errorf("expected a to take both tricks, got %+v", analysis)
+	}
+
+	record, _ := app.sessionStore.archive.Get("game")
+	if record.Rounds[0].DoubleDummy == nil {
+		t.Errorf("expected the result to be kept")
+	}
+
+	// Nothing is solved per request, it waits for its turn in the background
+	for range maxRunningReviews {
+		app.sessionStore.archive.slots <- struct{}{}
+	}
+	app.sessionStore.archive.Add(g.GameRecord{ID: "queued"})
+	resp, err = http.Get(server.URL + "/api/games/queued/double-dummy")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected status Accepted while queued; got %v", resp.Status)
+	}
+	for range maxRunningReviews {
+		<-app.sessionStore.archive.slots
+	}
+
+	resp, err = http.Get(server.URL + "/api/games/missing/double-dummy")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status Not Found; got %v", resp.Status)
+	}
+}
+
+func TestReviewHandler(t *testing.T) {
+	app := NewApp()
+	app.sessionStore.archive.Add(g.GameRecord{
+		ID:      "game",
+		Players: map[types.PlayerID]string{"a": "A"},
+	})
+
+	server := httptest.NewServer(app.RegisterRoutes())
+	defer server.Close()
+
+	<-app.sessionStore.archive.reviews["game"].done
+
+	resp, err := http.Get(server.URL + "/api/games/game/review")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK; got %v", resp.Status)
+	}
+
+	var review g.GameReview
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if review.GameID != "game" || review.Players["a"] == nil {
+		t.Errorf("expected a review of the game, got %+v", review)
+	}
+
+	resp, err = http.Get(server.URL + "/api/games/missing/review")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status Not Found; got %v", resp.Status)
+	}
+}
@@ -0,0 +1,82 @@
+package app
+
+// Seats the host fills with bots in the lobby. Bots have no connection, the
+// game plays for them, so they only live in the seating order and never in
+// players.
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+	"github.com/google/uuid"
+)
+
+func (s *Session) handleAddBot(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "add bots"); err != nil {
+		return err
+	}
+	if s.gameInProgress() {
+		return t.NewActionError(t.ErrWrongPhase, "Bots can only be added in the lobby")
+	}
+
+	s.mu.Lock()
+	if len(s.order) >= s.rules.MaxPlayers {
+		s.mu.Unlock()
+		return t.NewActionError(t.ErrNotAllowed, "The table is full")
+	}
+	id := t.PlayerID("bot-" + uuid.NewString())
+	name := fmt.Sprintf("Bot %d", len(s.bots)+1)
+	s.bots[id] = name
+	s.order = append(s.order, id)
+	s.mu.Unlock()
+
+	log.Printf("Bot (%v) added to session (%v)\n", name, s.ID)
+
+	broadcastPlayersUpdate(s)
+	return nil
+}
+
+// Kicking a bot takes it off the table, there is no connection to close
+func (s *Session) removeBot(id t.PlayerID) error {
+	if s.gameInProgress() {
+		return t.NewActionError(t.ErrWrongPhase, "Bots cannot leave during a game")
+	}
+
+	s.mu.Lock()
+	delete(s.bots, id)
+	s.order = slices.DeleteFunc(s.order, func(member t.PlayerID) bool {
+		return member == id
+	})
+	s.mu.Unlock()
+
+	broadcastPlayersUpdate(s)
+	return nil
+}
+
+func (s *Session) isBot(id t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.bots[id]
+	return ok
+}
+
+// Connected players and bots
+func (s *Session) hasMember(id t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, connected := s.players[id]
+	_, bot := s.bots[id]
+	return connected || bot
+}
+
+func (s *Session) nameLocked(id t.PlayerID) string {
+	if name, ok := s.bots[id]; ok {
+		return name
+	}
+	if player, ok := s.players[id]; ok {
+		return player.PlayerName
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestBots(t *testing.T) {
+	session := newTestSession("a")
+	if err := session.handleAddBot(testInput(session, "a", types.MsgAddBot, nil)); err != nil {
+		t.Fatalf("unexpected error adding a bot: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	session.AddPlayer(&types.Player{ID: "b", PlayerName: "b", Send: make(chan types.Envelope, 64), Ctx: ctx, Cancel: cancel})
+
+	if err := session.handleAddBot(testInput(session, "b", types.MsgAddBot, nil)); errorCode(err) != types.ErrNotHost {
+		t.Errorf("expected only the host to add bots, got %v", err)
+	}
+
+	// Kicking a bot takes it off the table
+	session.handleAddBot(testInput(session, "a", types.MsgAddBot, nil))
+	extra := session.Seats()[3]
+	if err := session.handleKickPlayer(testInput(session, "a", types.MsgKickPlayer, TargetPlayer{PlayerID: extra})); err != nil {
+		t.Fatalf("unexpected error kicking a bot: %v", err)
+	}
+
+	players := session.PublicPlayers()
+	if len(players) != 3 || !players[1].IsBot || !players[1].Ready {
+		t.Fatalf("expected a ready bot between a and b, got %+v", players)
+	}
+	bot := players[1].ID
+
+	// Bots never ready up themselves
+	for _, id := range []types.PlayerID{"a", "b"} {
+		session.handleSetReady(testInput(session, id, types.MsgSetReady, SetReady{Ready: true}))
+	}
+	if err := session.handleStartGame(testInput(session, "a", types.MsgStartGame, nil)); err != nil {
+		t.Fatalf("unexpected error starting with a bot: %v", err)
+	}
+	if !session.game.Players[bot].Bot {
+		t.Errorf("expected the bot to be seated")
+	}
+
+	// Host passes over the bot to the next connected player
+	session.RemovePlayer(session.players["a"])
+	if session.Host() != "b" {
+		t.Errorf("expected b to host, got %q", session.Host())
+	}
+}
+
+func TestClaimBotSeat(t *testing.T) {
+	session := newTestSession("a")
+	session.handleAddBot(testInput(session, "a", types.MsgAddBot, nil))
+	session.handleSetReady(testInput(session, "a", types.MsgSetReady, SetReady{Ready: true}))
+	if err := session.handleStartGame(testInput(session, "a", types.MsgStartGame, nil)); err != nil {
+		t.Fatalf("unexpected error starting with a bot: %v", err)
+	}
+	bot := session.Seats()[1]
+	hand := slices.Clone(session.game.Players[bot].Cards)
+
+	if vacant := session.Info().VacantSeats; len(vacant) != 1 || vacant[0].ID != bot {
+		t.Fatalf("expected the bot's seat to be up for grabs, got %+v", vacant)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session.AddPlayer(&types.Player{ID: "x", PlayerName: "late", Send: make(chan types.Envelope, 64), Ctx: ctx, Cancel: cancel})
+	if err := session.handleClaimSeat(testInput(session, "x", types.MsgClaimSeat, TargetPlayer{PlayerID: bot})); err != nil {
+		t.Fatalf("unexpected error claiming the bot's seat: %v", err)
+	}
+
+	seat := session.game.Players[bot]
+	if seat.Bot || seat.PlayerName != "late" || !slices.Equal(seat.Cards, hand) {
+		t.Errorf("expected late to play the bot's hand, got %+v", seat)
+	}
+	if session.isBot(bot) || !slices.Equal(session.Seats(), []types.PlayerID{"a", bot}) {
+		t.Errorf("expected the bot to be gone and the seating kept, got %v", session.Seats())
+	}
+	if vacant := session.Info().VacantSeats; len(vacant) != 0 {
+		t.Errorf("expected no open seats, got %+v", vacant)
+	}
+}
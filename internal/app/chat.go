@@ -0,0 +1,138 @@
+package app
+
+// In-session text chat, handled outside of the Game
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const (
+	maxChatHistory = 50
+	maxChatLength  = 500
+
+	chatRate  = 1.0 // messages per second
+	chatBurst = 5
+)
+
+type ChatSend struct {
+	Text string      `json:"text"`
+	To   *t.PlayerID `json:"to,omitempty"` // whisper to a single player
+}
+
+type ChatMessage struct {
+	From      t.PlayerID  `json:"from"`
+	Name      string      `json:"name"`
+	Text      string      `json:"text"`
+	To        *t.PlayerID `json:"to,omitempty"`
+	Timestamp int64       `json:"timestamp"` // unix millis, server time
+}
+
+type MutePlayer struct {
+	PlayerID t.PlayerID `json:"playerId"`
+	Muted    bool       `json:"muted"`
+}
+
+func (s *Session) handleChat(input t.GameInput) error {
+	var payload ChatSend
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid chat message")
+	}
+
+	text := strings.TrimSpace(payload.Text)
+	if text == "" || utf8.RuneCountInString(text) > maxChatLength {
+		return t.NewActionError(t.ErrInvalidPayload, "Chat message must be between 1 and 500 characters")
+	}
+
+	if s.muted[input.Player.ID] {
+		return t.NewActionError(t.ErrMuted, "You are muted")
+	}
+
+	now := time.Now()
+	if !s.chatLimiter.Allow(input.Player.ID, now) {
+		return t.NewActionError(t.ErrRateLimited, "You are sending messages too quickly")
+	}
+
+	msg := ChatMessage{
+		From:      input.Player.ID,
+		Name:      input.Player.PlayerName,
+		Text:      text,
+		To:        payload.To,
+		Timestamp: now.UnixMilli(),
+	}
+
+	var recipients []t.PlayerID
+	if msg.To != nil {
+		if *msg.To == msg.From {
+			return t.NewActionError(t.ErrNotAllowed, "You cannot whisper to yourself")
+		}
+		if !s.hasPlayer(*msg.To) {
+			return t.NewActionError(t.ErrNotFound, "Player not found")
+		}
+		recipients = []t.PlayerID{msg.From, *msg.To}
+	} else {
+		recipients = s.allPlayerIDs()
+	}
+
+	s.appendChatHistory(msg)
+
+	s.Emit(t.GameOutput{
+		Players: recipients,
+		Env: t.Envelope{
+			Type:    t.MsgChatMessage,
+			Payload: mustMarshal(msg),
+		},
+	})
+	return nil
+}
+
+func (s *Session) handleMute(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "mute players"); err != nil {
+		return err
+	}
+
+	var payload MutePlayer
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid mute request")
+	}
+
+	if payload.PlayerID == input.Player.ID {
+		return t.NewActionError(t.ErrNotAllowed, "You cannot mute yourself")
+	}
+
+	if payload.Muted {
+		s.muted[payload.PlayerID] = true
+	} else {
+		delete(s.muted, payload.PlayerID)
+	}
+	return nil
+}
+
+func (s *Session) appendChatHistory(msg ChatMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chatHistory = append(s.chatHistory, msg)
+	if len(s.chatHistory) > maxChatHistory {
+		s.chatHistory = s.chatHistory[len(s.chatHistory)-maxChatHistory:]
+	}
+}
+
+// Chat history visible to the given player, whispers are only visible to both ends
+func (s *Session) ChatHistoryFor(id t.PlayerID) []ChatMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]ChatMessage, 0, len(s.chatHistory))
+	for _, msg := range s.chatHistory {
+		if msg.To != nil && msg.From != id && *msg.To != id {
+			continue
+		}
+		history = append(history, msg)
+	}
+	return history
+}
@@ -0,0 +1,140 @@
+package app
+
+// Wire encodings of the envelope, picked per connection by the negotiated subprotocol.
+// Payloads stay JSON inside the session, binary codecs only change what goes over the wire.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/fxamacker/cbor/v2"
+)
+
+type Codec interface {
+	Read(ctx context.Context, conn *websocket.Conn) (t.Envelope, error)
+	Write(ctx context.Context, conn *websocket.Conn, env t.Envelope) error
+}
+
+var codecs = map[string]Codec{
+	"json": jsonCodec{},
+	"cbor": newCBORCodec(),
+}
+
+// ================= JSON =================
+
+type jsonCodec struct{}
+
+func (jsonCodec) Read(ctx context.Context, conn *websocket.Conn) (t.Envelope, error) {
+	var env t.Envelope
+	err := wsjson.Read(ctx, conn, &env)
+	return env, err
+}
+
+func (jsonCodec) Write(ctx context.Context, conn *websocket.Conn, env t.Envelope) error {
+	return wsjson.Write(ctx, conn, env)
+}
+
+// ================= CBOR =================
+
+type cborCodec struct {
+	enc cbor.EncMode
+	dec cbor.DecMode
+}
+
+// Same fields as Envelope with the payload as a native CBOR value
+type cborEnvelope struct {
+	ID      string        `cbor:"id,omitempty"`
+	Seq     uint64        `cbor:"seq,omitempty"`
+	Type    t.MessageType `cbor:"type"`
+	Payload any           `cbor:"payload,omitempty"`
+}
+
+func newCBORCodec() cborCodec {
+	enc, err := cbor.EncOptions{ShortestFloat: cbor.ShortestFloat16}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	// Maps decode with string keys so payloads can be turned back into JSON
+	dec, err := cbor.DecOptions{DefaultMapType: reflect.TypeFor[map[string]any]()}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return cborCodec{enc: enc, dec: dec}
+}
+
+func (c cborCodec) Read(ctx context.Context, conn *websocket.Conn) (t.Envelope, error) {
+	typ, data, err := conn.Read(ctx)
+	if err != nil {
+		return t.Envelope{}, err
+	}
+	if typ != websocket.MessageBinary {
+		return t.Envelope{}, fmt.Errorf("expected binary message, got %v", typ)
+	}
+	return c.unmarshal(data)
+}
+
+func (c cborCodec) Write(ctx context.Context, conn *websocket.Conn, env t.Envelope) error {
+	data, err := c.marshal(env)
+	if err != nil {
+		return err
+	}
+	return conn.Write(ctx, websocket.MessageBinary, data)
+}
+
+func (c cborCodec) marshal(env t.Envelope) ([]byte, error) {
+	wire := cborEnvelope{ID: env.ID, Seq: env.Seq, Type: env.Type}
+
+	if len(env.Payload) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(env.Payload))
+		dec.UseNumber()
+		if err := dec.Decode(&wire.Payload); err != nil {
+			return nil, fmt.Errorf("failed to read %s payload: %w", env.Type, err)
+		}
+		wire.Payload = nativeNumbers(wire.Payload)
+	}
+	return c.enc.Marshal(wire)
+}
+
+func (c cborCodec) unmarshal(data []byte) (t.Envelope, error) {
+	var wire cborEnvelope
+	if err := c.dec.Unmarshal(data, &wire); err != nil {
+		return t.Envelope{}, fmt.Errorf("failed to decode cbor message: %w", err)
+	}
+
+	env := t.Envelope{ID: wire.ID, Seq: wire.Seq, Type: wire.Type}
+	if wire.Payload != nil {
+		payload, err := json.Marshal(wire.Payload)
+		if err != nil {
+			return t.Envelope{}, fmt.Errorf("failed to convert %s payload: %w", wire.Type, err)
+		}
+		env.Payload = payload
+	}
+	return env, nil
+}
+
+// JSON numbers become CBOR integers where possible instead of floats
+func nativeNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = nativeNumbers(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = nativeNumbers(elem)
+		}
+	}
+	return v
+}
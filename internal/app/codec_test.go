@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	codec := newCBORCodec()
+
+	payload := `{"bid":3,"ratio":0.5,"name":"a","tags":["x","y"],"nested":{"ok":true,"none":null}}`
+	env := types.Envelope{ID: "1", Seq: 7, Type: types.MsgMakeBid, Payload: json.RawMessage(payload)}
+
+	data, err := codec.marshal(env)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if asJSON, _ := json.Marshal(env); len(data) >= len(asJSON) {
+		t.Errorf("expected cbor to be smaller than json, got %d >= %d bytes", len(data), len(asJSON))
+	}
+
+	got, err := codec.unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got.ID != env.ID || got.Seq != env.Seq || got.Type != env.Type {
+		t.Errorf("expected envelope fields to survive, got %+v", got)
+	}
+
+	var want, have any
+	json.Unmarshal(env.Payload, &want)
+	json.Unmarshal(got.Payload, &have)
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("expected payload %s, got %s", env.Payload, got.Payload)
+	}
+}
+
+func TestCBORNoPayload(t *testing.T) {
+	codec := newCBORCodec()
+
+	data, err := codec.marshal(types.Envelope{Type: types.MsgStartGame})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	got, err := codec.unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got.Type != types.MsgStartGame || got.Payload != nil {
+		t.Errorf("expected bare start_game, got %+v", got)
+	}
+
+	if _, err := codec.unmarshal([]byte{0xff, 0x00}); err == nil {
+		t.Errorf("expected garbage to be rejected")
+	}
+}
@@ -0,0 +1,209 @@
+package app
+
+// Translation between the current protocol and older clients
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Before v3 cards were objects and suits were numbers
+type legacyCard struct {
+	Suit int `json:"suit"`
+	Rank int `json:"rank"`
+}
+
+func toLegacyCard(card g.Card) legacyCard {
+	return legacyCard{Suit: int(card.Suit), Rank: int(card.Rank)}
+}
+
+// v1 name of error, its payload already carried the message
+const legacyInvalidAction t.MessageType = "invalid_action"
+
+// Rewrites a message from the session for this connection
+func (p Protocol) outbound(env t.Envelope) t.Envelope {
+	if p.Version < ProtocolV3 {
+		env = legacyCardsOut(env)
+	}
+	if p.Version < ProtocolV2 && env.Type == t.MsgError {
+		env.Type = legacyInvalidAction
+	}
+
+	if env.Type != t.MsgWelcome {
+		return env
+	}
+
+	var welcome Welcome
+	if err := json.Unmarshal(env.Payload, &welcome); err != nil {
+		return env
+	}
+
+	if p.Version < ProtocolV2 {
+		env.Payload = mustMarshal(welcome.PlayerID)
+		return env
+	}
+	welcome.ProtocolVersion = p.Version
+	env.Payload = mustMarshal(welcome)
+	return env
+}
+
+// Rewrites a message from this connection for the session
+func (p Protocol) inbound(env t.Envelope) t.Envelope {
+	if p.Version < ProtocolV3 && env.Type == t.MsgPlayCard {
+		var legacy legacyCard
+		if err := json.Unmarshal(env.Payload, &legacy); err != nil {
+			return env // not the object form, validation decides
+		}
+		if text, err := (g.Card{Suit: g.Suit(legacy.Suit), Rank: g.Rank(legacy.Rank)}).MarshalText(); err == nil {
+			env.Payload = mustMarshal(string(text))
+		}
+	}
+	return env
+}
+
+// Game events v1 clients don't know, they get a state_sync instead
+var legacyStateEvents = map[t.MessageType]bool{
+	t.MsgRoundStarted: true,
+	t.MsgBidPlaced:    true,
+	t.MsgCardPlayed:   true,
+	t.MsgTrickWon:     true,
+	t.MsgRoundScored:  true,
+	t.MsgTurnChanged:  true,
+}
+
+// Sends v1 clients the whole view once the input or tick that moved the
+// game is done, like every move did before events existed
+func (s *Session) syncLegacyPlayers() {
+	s.mu.Lock()
+	ids := slices.Collect(maps.Keys(s.resync))
+	clear(s.resync)
+	s.mu.Unlock()
+
+	if s.game != nil && len(ids) > 0 {
+		s.game.SyncState(ids)
+	}
+}
+
+// Fields holding cards or suits in the messages that carry them.
+// player_hand is left alone, it always used the long text form.
+var legacyCardFields = map[t.MessageType]map[string]func(json.RawMessage) json.RawMessage{
+	t.MsgStateSync: {
+		"trumpSuit":  legacySuit,
+		"table":      legacyCardMap,
+		"hand":       legacyCardList,
+		"legalCards": legacyCardList,
+		"lastTrick":  legacyTrickValue,
+	},
+	t.MsgCardPlayed: {
+		"trumpSuit": legacySuit,
+		"card":      legacyCardValue,
+	},
+	t.MsgTurnChanged: {
+		"legalCards": legacyCardList,
+	},
+	t.MsgTrickHistory: {
+		"tricks": legacyTrickList,
+	},
+}
+
+func legacyCardsOut(env t.Envelope) t.Envelope {
+	fields, ok := legacyCardFields[env.Type]
+	if !ok {
+		return env
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(env.Payload, &payload); err != nil {
+		return env
+	}
+	for name, convert := range fields {
+		if raw, ok := payload[name]; ok {
+			payload[name] = convert(raw)
+		}
+	}
+	env.Payload = mustMarshal(payload)
+	return env
+}
+
+func legacySuit(raw json.RawMessage) json.RawMessage {
+	var suit *g.Suit
+	if err := json.Unmarshal(raw, &suit); err != nil || suit == nil {
+		return raw
+	}
+	return mustMarshal(int(*suit))
+}
+
+func legacyCardValue(raw json.RawMessage) json.RawMessage {
+	var card g.Card
+	if err := json.Unmarshal(raw, &card); err != nil {
+		return raw
+	}
+	return mustMarshal(toLegacyCard(card))
+}
+
+func legacyCardList(raw json.RawMessage) json.RawMessage {
+	var cards []g.Card
+	if err := json.Unmarshal(raw, &cards); err != nil {
+		return raw
+	}
+	legacy := make([]legacyCard, len(cards))
+	for i, card := range cards {
+		legacy[i] = toLegacyCard(card)
+	}
+	return mustMarshal(legacy)
+}
+
+func legacyCardMap(raw json.RawMessage) json.RawMessage {
+	var cards map[t.PlayerID]g.Card
+	if err := json.Unmarshal(raw, &cards); err != nil {
+		return raw
+	}
+	legacy := make(map[t.PlayerID]legacyCard, len(cards))
+	for id, card := range cards {
+		legacy[id] = toLegacyCard(card)
+	}
+	return mustMarshal(legacy)
+}
+
+type legacyPlay struct {
+	Player t.PlayerID `json:"player"`
+	Card   legacyCard `json:"card"`
+}
+
+type legacyTrick struct {
+	Leader t.PlayerID   `json:"leader"`
+	Plays  []legacyPlay `json:"plays"`
+	Winner t.PlayerID   `json:"winner"`
+}
+
+func toLegacyTrick(trick g.Trick) legacyTrick {
+	legacy := legacyTrick{Leader: trick.Leader, Winner: trick.Winner}
+	for _, play := range trick.Plays {
+		legacy.Plays = append(legacy.Plays, legacyPlay{Player: play.Player, Card: toLegacyCard(play.Card)})
+	}
+	return legacy
+}
+
+func legacyTrickValue(raw json.RawMessage) json.RawMessage {
+	var trick *g.Trick
+	if err := json.Unmarshal(raw, &trick); err != nil || trick == nil {
+		return raw
+	}
+	return mustMarshal(toLegacyTrick(*trick))
+}
+
+func legacyTrickList(raw json.RawMessage) json.RawMessage {
+	var tricks []g.Trick
+	if err := json.Unmarshal(raw, &tricks); err != nil {
+		return raw
+	}
+	legacy := make([]legacyTrick, len(tricks))
+	for i, trick := range tricks {
+		legacy[i] = toLegacyTrick(trick)
+	}
+	return mustMarshal(legacy)
+}
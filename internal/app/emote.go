@@ -0,0 +1,81 @@
+package app
+
+// Quick reactions broadcast to the table, handled outside of the Game
+
+import (
+	"encoding/json"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const (
+	emoteRate  = 0.5 // emotes per second
+	emoteBurst = 3
+)
+
+type Emote string
+
+const (
+	EmoteNiceTrick Emote = "nice_trick"
+	EmoteOuch      Emote = "ouch"
+	EmoteThumbsUp  Emote = "👍"
+	EmoteWellBid   Emote = "well_bid"
+	EmoteHurryUp   Emote = "hurry_up"
+	EmoteLaugh     Emote = "😂"
+)
+
+var validEmotes = map[Emote]bool{
+	EmoteNiceTrick: true,
+	EmoteOuch:      true,
+	EmoteThumbsUp:  true,
+	EmoteWellBid:   true,
+	EmoteHurryUp:   true,
+	EmoteLaugh:     true,
+}
+
+type EmoteSend struct {
+	Emote  Emote       `json:"emote"`
+	Target *t.PlayerID `json:"target,omitempty"`
+}
+
+type EmoteReaction struct {
+	From      t.PlayerID  `json:"from"`
+	Emote     Emote       `json:"emote"`
+	Target    *t.PlayerID `json:"target,omitempty"`
+	Timestamp int64       `json:"timestamp"` // unix millis, server time
+}
+
+func (s *Session) handleEmote(input t.GameInput) error {
+	var payload EmoteSend
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid emote")
+	}
+
+	if !validEmotes[payload.Emote] {
+		return t.NewActionError(t.ErrInvalidPayload, "Unknown emote")
+	}
+
+	if payload.Target != nil && !s.hasPlayer(*payload.Target) {
+		return t.NewActionError(t.ErrNotFound, "Player not found")
+	}
+
+	now := time.Now()
+	if !s.emoteLimiter.Allow(input.Player.ID, now) {
+		return t.NewActionError(t.ErrRateLimited, "You are sending emotes too quickly")
+	}
+
+	s.Emit(t.GameOutput{
+		Players: s.allPlayerIDs(),
+		Env: t.Envelope{
+			Type: t.MsgEmote,
+			Payload: mustMarshal(EmoteReaction{
+				From:      input.Player.ID,
+				Emote:     payload.Emote,
+				Target:    payload.Target,
+				Timestamp: now.UnixMilli(),
+			}),
+		},
+	})
+	return nil
+}
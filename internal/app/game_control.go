@@ -0,0 +1,123 @@
+package app
+
+// Pausing and abandoning a game in progress
+
+import (
+	"encoding/json"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type AbandonVote struct {
+	Abandon bool `json:"abandon"`
+}
+
+type AbandonVoteStatus struct {
+	Votes  []t.PlayerID `json:"votes"`
+	Needed int          `json:"needed"`
+}
+
+func (s *Session) gameInProgress() bool {
+	return s.game != nil && !s.game.IsOver()
+}
+
+// Advances turn timers, called from the run loop
+func (s *Session) tick(now time.Time) {
+	if !s.gameInProgress() {
+		return
+	}
+	s.game.Tick(now)
+
+	// Players leaving can leave a majority behind
+	if status := s.abandonStatus(); !s.game.IsOver() && len(status.Votes) >= status.Needed {
+		s.game.Abandon()
+	}
+	if s.game.IsOver() {
+		s.finishGame()
+	}
+}
+
+func (s *Session) handlePauseGame(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "pause the game"); err != nil {
+		return err
+	}
+	if !s.gameInProgress() {
+		return t.NewActionError(t.ErrNoGame, "No game in progress")
+	}
+	s.game.Pause(time.Now())
+	return nil
+}
+
+func (s *Session) handleResumeGame(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "resume the game"); err != nil {
+		return err
+	}
+	if !s.gameInProgress() {
+		return t.NewActionError(t.ErrNoGame, "No game in progress")
+	}
+	s.game.Resume(time.Now())
+	return nil
+}
+
+// Seated players vote to end the game, a majority ends it
+func (s *Session) handleAbandonVote(input t.GameInput) error {
+	if !s.gameInProgress() {
+		return t.NewActionError(t.ErrNoGame, "No game in progress")
+	}
+	if _, seated := s.game.Players[input.Player.ID]; !seated {
+		return t.NewActionError(t.ErrNotSeated, "Only seated players can vote")
+	}
+
+	payload := AbandonVote{Abandon: true}
+	if len(input.Env.Payload) > 0 {
+		if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+			return t.NewActionError(t.ErrInvalidPayload, "Invalid vote")
+		}
+	}
+
+	s.mu.Lock()
+	if payload.Abandon {
+		s.abandonVotes[input.Player.ID] = true
+	} else {
+		delete(s.abandonVotes, input.Player.ID)
+	}
+	s.mu.Unlock()
+
+	status := s.abandonStatus()
+	s.Emit(t.GameOutput{
+		Players: s.allPlayerIDs(),
+		Env: t.Envelope{
+			Type:    t.MsgAbandonVote,
+			Payload: mustMarshal(status),
+		},
+	})
+
+	if len(status.Votes) >= status.Needed {
+		s.game.Abandon()
+		s.finishGame()
+	}
+	return nil
+}
+
+// A majority of the seated players still connected is needed
+func (s *Session) abandonStatus() AbandonVoteStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connected := 0
+	for id := range s.game.Players {
+		if _, ok := s.players[id]; ok {
+			connected++
+		}
+	}
+
+	status := AbandonVoteStatus{
+		Votes:  make([]t.PlayerID, 0, len(s.abandonVotes)),
+		Needed: connected/2 + 1,
+	}
+	for id := range s.abandonVotes {
+		status.Votes = append(status.Votes, id)
+	}
+	return status
+}
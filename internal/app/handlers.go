@@ -2,28 +2,214 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func (a *App) CreateSessionHandler(w http.ResponseWriter, r *http.Request) {
-	session := a.sessionStore.GenerateRandomSession()
+	// Body is optional, no body creates a public session
+	var req CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session := a.sessionStore.GenerateRandomSession(SessionAccess{
+		Private:  req.Private,
+		Passcode: req.Passcode,
+	})
+
+	resp := CreateSessionResponse{
+		SessionID: session.ID,
+		Private:   session.IsPrivate(),
+	}
+	if session.IsPrivate() {
+		token, expiresAt := a.invites.Sign(session.ID, time.Now())
+		resp.Invite = &Invite{Token: token, ExpiresAt: expiresAt}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(session); err != nil {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// Anyone who can join a private session can mint a fresh invite to share
+func (a *App) CreateInviteHandler(w http.ResponseWriter, r *http.Request) {
+	sessionId := chi.URLParam(r, "sessionId")
+
+	session, exists := a.sessionStore.GetSession(sessionId)
+	if !exists {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	var req InviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.authorizeJoin(session, req.Passcode, req.Invite); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	token, expiresAt := a.invites.Sign(session.ID, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Invite{Token: token, ExpiresAt: expiresAt}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (a *App) authorizeJoin(session *Session, passcode string, invite string) error {
+	if !session.IsPrivate() {
+		return nil
+	}
+	if session.CheckPasscode(passcode) {
+		return nil
+	}
+	if invite == "" {
+		return errors.New("private session requires a passcode or invite")
+	}
+	return a.invites.Verify(invite, session.ID, time.Now())
+}
+
 func (a *App) GetSessionHandler(w http.ResponseWriter, r *http.Request) {
 	sessionId := chi.URLParam(r, "sessionId")
 
-	if _, exists := a.sessionStore.GetSession(sessionId); !exists {
+	session, exists := a.sessionStore.GetSession(sessionId)
+	if !exists {
 		http.Error(w, "session not found", http.StatusNotFound)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(session.Info()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Lists joinable sessions. Finished sessions are only listed when asked for with ?state=finished
+func (a *App) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	state := SessionState(query.Get("state"))
+	switch state {
+	case "", SessionLobby, SessionInGame, SessionFinished:
+	default:
+		http.Error(w, "invalid state filter", http.StatusBadRequest)
+		return
+	}
+
+	openOnly := false
+	if open := query.Get("open"); open != "" {
+		var err error
+		if openOnly, err = strconv.ParseBool(open); err != nil {
+			http.Error(w, "invalid open filter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	infos := make([]SessionInfo, 0)
+	for _, session := range a.sessionStore.ListSessions() {
+		if session.ctx.Err() != nil {
+			continue // emptied out, waiting to be cleaned up
+		}
+		if session.IsPrivate() {
+			continue
+		}
+
+		info := session.Info()
+		if state == "" && info.State == SessionFinished {
+			continue
+		}
+		if state != "" && info.State != state {
+			continue
+		}
+		if openOnly && info.Seats.Open == 0 {
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	// Newest first
+	slices.SortFunc(infos, func(a, b SessionInfo) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(infos); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Finished games, looked up by the gameId sent with game_end
+func (a *App) GetGameHandler(w http.ResponseWriter, r *http.Request) {
+	record, exists := a.sessionStore.archive.Get(chi.URLParam(r, "gameId"))
+	if !exists {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(record); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Rounds too large to solve while the game was running are solved in the
+// background once it is archived, like reviews
+func (a *App) GetDoubleDummyHandler(w http.ResponseWriter, r *http.Request) {
+	analysis, ready, exists := a.sessionStore.archive.DoubleDummy(chi.URLParam(r, "gameId"))
+	if !exists {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"status": "pending"})
+		return
+	}
+	if err := json.NewEncoder(w).Encode(analysis); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Reviews are worked out in the background once a game is archived,
+// until then the client is told to come back later
+func (a *App) GetReviewHandler(w http.ResponseWriter, r *http.Request) {
+	review, ready, exists := a.sessionStore.archive.Review(chi.URLParam(r, "gameId"))
+	if !exists {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"status": "pending"})
+		return
+	}
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 }
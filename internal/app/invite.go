@@ -0,0 +1,92 @@
+package app
+
+// Signed, expiring invite tokens for private sessions
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultInviteTTL = 24 * time.Hour
+
+var (
+	ErrInvalidInvite = errors.New("invalid invite token")
+	ErrExpiredInvite = errors.New("invite token expired")
+)
+
+type InviteSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewInviteSigner(secret []byte, ttl time.Duration) *InviteSigner {
+	return &InviteSigner{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// Uses INVITE_SECRET when set, otherwise tokens only live as long as the process
+func NewInviteSignerFromEnv() *InviteSigner {
+	secret := []byte(os.Getenv("INVITE_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+	return NewInviteSigner(secret, defaultInviteTTL)
+}
+
+// Token format: base64(sessionId.expiry).base64(hmac)
+func (is *InviteSigner) Sign(sessionId string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(is.ttl).Truncate(time.Second)
+	body := sessionId + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	token := base64.RawURLEncoding.EncodeToString([]byte(body)) + "." +
+		base64.RawURLEncoding.EncodeToString(is.mac(body))
+	return token, expiresAt
+}
+
+func (is *InviteSigner) Verify(token string, sessionId string, now time.Time) error {
+	encBody, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidInvite
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encBody)
+	if err != nil {
+		return ErrInvalidInvite
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil {
+		return ErrInvalidInvite
+	}
+	if !hmac.Equal(sig, is.mac(string(body))) {
+		return ErrInvalidInvite
+	}
+
+	id, expiry, ok := strings.Cut(string(body), ".")
+	if !ok || id != sessionId {
+		return ErrInvalidInvite
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return ErrInvalidInvite
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return ErrExpiredInvite
+	}
+	return nil
+}
+
+func (is *InviteSigner) mac(body string) []byte {
+	h := hmac.New(sha256.New, is.secret)
+	h.Write([]byte(body))
+	return h.Sum(nil)
+}
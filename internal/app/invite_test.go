@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInviteSigner(t *testing.T) {
+	signer := NewInviteSigner([]byte("secret"), time.Hour)
+	now := time.Now()
+
+	token, expiresAt := signer.Sign("abcdefgh", now)
+	if !expiresAt.After(now) {
+		t.Fatalf("expected expiry in the future, got %v", expiresAt)
+	}
+
+	if err := signer.Verify(token, "abcdefgh", now); err != nil {
+		t.Errorf("expected token to verify: %v", err)
+	}
+
+	// Token is bound to its session
+	if err := signer.Verify(token, "zzzzzzzz", now); err != ErrInvalidInvite {
+		t.Errorf("expected ErrInvalidInvite for other session, got %v", err)
+	}
+
+	if err := signer.Verify(token, "abcdefgh", now.Add(2*time.Hour)); err != ErrExpiredInvite {
+		t.Errorf("expected ErrExpiredInvite, got %v", err)
+	}
+
+	// Tokens signed with another secret are rejected
+	other := NewInviteSigner([]byte("other"), time.Hour)
+	forged, _ := other.Sign("abcdefgh", now)
+	if err := signer.Verify(forged, "abcdefgh", now); err != ErrInvalidInvite {
+		t.Errorf("expected ErrInvalidInvite for forged token, got %v", err)
+	}
+
+	if err := signer.Verify("garbage", "abcdefgh", now); err != ErrInvalidInvite {
+		t.Errorf("expected ErrInvalidInvite for garbage, got %v", err)
+	}
+}
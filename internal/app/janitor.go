@@ -0,0 +1,130 @@
+package app
+
+// Removes sessions that are no longer in use
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+type ReapReason string
+
+const (
+	ReapCancelled ReapReason = "cancelled"
+	ReapUnjoined  ReapReason = "unjoined"
+	ReapIdle      ReapReason = "idle"
+)
+
+type JanitorConfig struct {
+	Interval    time.Duration // how often to sweep
+	UnjoinedTTL time.Duration // how long a session may wait for its first player
+	IdleTTL     time.Duration // how long a session may go without activity
+}
+
+func DefaultJanitorConfig() JanitorConfig {
+	return JanitorConfig{
+		Interval:    time.Minute,
+		UnjoinedTTL: 10 * time.Minute,
+		IdleTTL:     time.Hour,
+	}
+}
+
+// Overrides defaults with SESSION_JANITOR_INTERVAL, SESSION_UNJOINED_TTL and SESSION_IDLE_TTL
+func LoadJanitorConfig() JanitorConfig {
+	cfg := DefaultJanitorConfig()
+	loadDuration("SESSION_JANITOR_INTERVAL", &cfg.Interval)
+	loadDuration("SESSION_UNJOINED_TTL", &cfg.UnjoinedTTL)
+	loadDuration("SESSION_IDLE_TTL", &cfg.IdleTTL)
+	return cfg
+}
+
+func loadDuration(key string, dst *time.Duration) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		log.Printf("Ignoring invalid %s=%q", key, val)
+		return
+	}
+	*dst = d
+}
+
+func (a *App) StartJanitor(ctx context.Context, cfg JanitorConfig) {
+	go a.sessionStore.runJanitor(ctx, cfg)
+}
+
+func (s *SessionStore) runJanitor(ctx context.Context, cfg JanitorConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Sweep(now, cfg)
+		}
+	}
+}
+
+// Told to players still connected when their session is removed
+var reapMessages = map[ReapReason]string{
+	ReapUnjoined: "session was never joined",
+	ReapIdle:     "session idle for too long",
+}
+
+// Removes expired sessions and returns why each one was removed
+func (s *SessionStore) Sweep(now time.Time, cfg JanitorConfig) map[string]ReapReason {
+	reaped := make(map[string]ReapReason)
+	var removed []*Session
+
+	s.mu.Lock()
+	for id, session := range s.sessions {
+		reason, expired := session.expired(now, cfg)
+		if !expired {
+			continue
+		}
+		delete(s.sessions, id)
+		reaped[id] = reason
+		removed = append(removed, session)
+	}
+	s.mu.Unlock()
+
+	// Closed outside the store lock, closing takes each session's own lock
+	for _, session := range removed {
+		reason := reaped[session.ID]
+		if reason != ReapCancelled {
+			session.Close(reapMessages[reason])
+		}
+
+		log.Printf("Session (%v) removed: %v", session.ID, reason)
+		sessionsActive.Add(-1)
+		sessionsReaped.Add(string(reason), 1)
+	}
+
+	return reaped
+}
+
+// Reports whether the session should be removed and why, without touching it
+func (s *Session) expired(now time.Time, cfg JanitorConfig) (ReapReason, bool) {
+	if s.ctx.Err() != nil {
+		return ReapCancelled, true
+	}
+
+	s.mu.Lock()
+	joined := s.joined
+	lastActivity := s.lastActivity
+	s.mu.Unlock()
+
+	if !joined && now.Sub(s.createdAt) > cfg.UnjoinedTTL {
+		return ReapUnjoined, true
+	}
+	if now.Sub(lastActivity) > cfg.IdleTTL {
+		return ReapIdle, true
+	}
+	return "", false
+}
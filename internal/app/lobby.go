@@ -0,0 +1,184 @@
+package app
+
+// Host controls for the session lobby
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+	"github.com/coder/websocket"
+)
+
+func (s *Session) Rules() g.Rules {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rules
+}
+
+func (s *Session) requireHost(id t.PlayerID, action string) error {
+	if s.isHost(id) {
+		return nil
+	}
+	return t.NewActionError(t.ErrNotHost, "Only the host can "+action)
+}
+
+func (s *Session) handleStartGame(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "start the game"); err != nil {
+		return err
+	}
+	if s.game != nil {
+		return t.NewActionError(t.ErrWrongPhase, "Game already started")
+	}
+
+	// Everyone connected takes a seat, in join order
+	seats := s.Seats()
+	if err := s.checkCanStart(seats); err != nil {
+		return t.NewActionError(t.ErrNotAllowed, err.Error())
+	}
+
+	s.startGame(seats, "")
+	return nil
+}
+
+// Seats the given players in a new game, they have to ready up again for the next one
+func (s *Session) startGame(seats []t.PlayerID, dealer t.PlayerID) {
+	s.mu.Lock()
+	clear(s.ready)
+	clear(s.abandonVotes)
+	s.mu.Unlock()
+
+	s.game = g.NewGame(s, s.Rules(), seats, dealer)
+	s.seatPlayers()
+	s.setState(SessionInGame)
+	s.game.Start()
+	broadcastPlayersUpdate(s)
+}
+
+// Checks that the given players can start a game together
+func (s *Session) checkCanStart(seats []t.PlayerID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rules.CheckPlayerCount(len(seats)); err != nil {
+		return err
+	}
+
+	for _, id := range seats {
+		if _, bot := s.bots[id]; !bot && !s.ready[id] {
+			return fmt.Errorf("%s is not ready", s.players[id].PlayerName)
+		}
+	}
+	return nil
+}
+
+func (s *Session) handleSetReady(input t.GameInput) error {
+	if s.gameInProgress() {
+		return t.NewActionError(t.ErrWrongPhase, "Game already started")
+	}
+
+	var payload SetReady
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid ready request")
+	}
+
+	s.mu.Lock()
+	s.ready[input.Player.ID] = payload.Ready
+	s.mu.Unlock()
+
+	broadcastPlayersUpdate(s)
+	return nil
+}
+
+func (s *Session) handleConfigureGame(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "change the rules"); err != nil {
+		return err
+	}
+	if s.game != nil {
+		return t.NewActionError(t.ErrWrongPhase, "Rules cannot be changed during a game")
+	}
+
+	var rules g.Rules
+	if err := json.Unmarshal(input.Env.Payload, &rules); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid rules")
+	}
+	if err := rules.Validate(); err != nil {
+		return t.NewActionError(t.ErrNotAllowed, err.Error())
+	}
+
+	// Everyone has to confirm again under the new rules
+	s.mu.Lock()
+	s.rules = rules
+	clear(s.ready)
+	s.mu.Unlock()
+
+	broadcastPlayersUpdate(s)
+
+	s.Emit(t.GameOutput{
+		Players: s.allPlayerIDs(),
+		Env: t.Envelope{
+			Type:    t.MsgRulesUpdate,
+			Payload: mustMarshal(rules),
+		},
+	})
+	return nil
+}
+
+func (s *Session) handleKickPlayer(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "kick players"); err != nil {
+		return err
+	}
+
+	var payload TargetPlayer
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid kick request")
+	}
+	if payload.PlayerID == input.Player.ID {
+		return t.NewActionError(t.ErrNotAllowed, "You cannot kick yourself")
+	}
+	if s.isBot(payload.PlayerID) {
+		return s.removeBot(payload.PlayerID)
+	}
+
+	s.mu.Lock()
+	player, ok := s.players[payload.PlayerID]
+	s.mu.Unlock()
+
+	if !ok {
+		return t.NewActionError(t.ErrNotFound, "Player not found")
+	}
+
+	log.Printf("Player (%v) kicked from session (%v)\n", player.PlayerName, s.ID)
+
+	// Closing the connection ends the read loop, which removes the player.
+	// Close waits for the handshake so keep it off the run loop.
+	go player.Conn.Close(websocket.StatusPolicyViolation, "kicked by host")
+	return nil
+}
+
+func (s *Session) handleTransferHost(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "transfer host"); err != nil {
+		return err
+	}
+
+	var payload TargetPlayer
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid transfer request")
+	}
+
+	s.mu.Lock()
+	_, ok := s.players[payload.PlayerID]
+	if ok {
+		s.host = payload.PlayerID
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return t.NewActionError(t.ErrNotFound, "Player not found")
+	}
+
+	broadcastPlayersUpdate(s)
+	return nil
+}
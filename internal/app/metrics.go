@@ -0,0 +1,10 @@
+package app
+
+import "expvar"
+
+// Session lifecycle metrics, served on /debug/vars of the admin listener
+var (
+	sessionsCreated = expvar.NewInt("sessions_created")
+	sessionsActive  = expvar.NewInt("sessions_active")
+	sessionsReaped  = expvar.NewMap("sessions_reaped") // keyed by reason
+)
@@ -1,8 +1,51 @@
 package app
 
-import t "github.com/B33Boy/Judgement/internal/types"
+import (
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type Welcome struct {
+	PlayerID        t.PlayerID      `json:"playerId"`
+	ProtocolVersion ProtocolVersion `json:"protocolVersion"`
+}
 
 type PlayerPublic struct {
-	ID   t.PlayerID `json:"id"`
-	Name string     `json:"name"`
+	ID     t.PlayerID `json:"id"`
+	Name   string     `json:"name"`
+	IsHost bool       `json:"isHost"`
+	Ready  bool       `json:"ready"`
+	IsBot  bool       `json:"isBot,omitempty"`
+}
+
+type SetReady struct {
+	Ready bool `json:"ready"`
+}
+
+type TargetPlayer struct {
+	PlayerID t.PlayerID `json:"playerId"`
+}
+
+// ================= REST =================
+
+type CreateSessionRequest struct {
+	Private  bool   `json:"private"`
+	Passcode string `json:"passcode"` // implies private
+}
+
+type CreateSessionResponse struct {
+	SessionID string  `json:"sessionId"`
+	Private   bool    `json:"private"`
+	Invite    *Invite `json:"invite,omitempty"`
+}
+
+type InviteRequest struct {
+	Passcode string `json:"passcode"`
+	Invite   string `json:"invite"`
+}
+
+type Invite struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
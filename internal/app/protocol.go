@@ -0,0 +1,90 @@
+package app
+
+// Versioned websocket subprotocols, e.g. "judgement.v3+json".
+// Sessions always speak the current version, compat.go translates
+// for connections that negotiated an older one.
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type ProtocolVersion int
+
+const (
+	ProtocolV1 ProtocolVersion = 1 // welcome payload is the bare player id, the game is followed through state_sync
+	ProtocolV2 ProtocolVersion = 2 // welcome carries the negotiated version
+	ProtocolV3 ProtocolVersion = 3 // cards and suits are sent as text, e.g. "SPADE-ACE"
+
+	CurrentProtocol = ProtocolV3
+)
+
+const subprotocolPrefix = "judgement."
+
+type Protocol struct {
+	Version  ProtocolVersion
+	Encoding string
+}
+
+// Newest first, the server prefers the first one the client also offers
+var supportedProtocols = []Protocol{
+	{Version: ProtocolV3, Encoding: "cbor"},
+	{Version: ProtocolV3, Encoding: "json"},
+	{Version: ProtocolV2, Encoding: "cbor"},
+	{Version: ProtocolV2, Encoding: "json"},
+}
+
+// Clients from before subprotocols were negotiated offer none
+var legacyProtocol = Protocol{Version: ProtocolV1, Encoding: "json"}
+
+func (p Protocol) Name() string {
+	return fmt.Sprintf("%sv%d+%s", subprotocolPrefix, p.Version, p.Encoding)
+}
+
+func (p Protocol) codec() Codec {
+	return codecs[p.Encoding]
+}
+
+func ParseProtocol(name string) (Protocol, error) {
+	var p Protocol
+	rest, ok := strings.CutPrefix(strings.ToLower(name), subprotocolPrefix)
+	if !ok {
+		return p, fmt.Errorf("unknown subprotocol %q", name)
+	}
+	version, encoding, ok := strings.Cut(rest, "+")
+	if !ok || encoding == "" {
+		return p, fmt.Errorf("subprotocol %q has no encoding", name)
+	}
+	if _, err := fmt.Sscanf(version, "v%d", &p.Version); err != nil {
+		return p, fmt.Errorf("subprotocol %q has no version", name)
+	}
+	p.Encoding = encoding
+	return p, nil
+}
+
+func subprotocolNames() []string {
+	names := make([]string, len(supportedProtocols))
+	for i, p := range supportedProtocols {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Picks the protocol of an accepted connection. Clients that offered
+// subprotocols but none we support get an error to close with.
+func negotiateProtocol(r *http.Request, selected string) (Protocol, error) {
+	if selected != "" {
+		return ParseProtocol(selected)
+	}
+	if len(r.Header.Values("Sec-WebSocket-Protocol")) == 0 {
+		return legacyProtocol, nil
+	}
+	// Close reasons are limited to 123 bytes so leave out the repeated prefix
+	versions := make([]string, len(supportedProtocols))
+	for i, p := range supportedProtocols {
+		versions[i] = strings.TrimPrefix(p.Name(), subprotocolPrefix)
+	}
+	return Protocol{}, fmt.Errorf("unsupported protocol version, server speaks %s{%s}",
+		subprotocolPrefix, strings.Join(versions, ","))
+}
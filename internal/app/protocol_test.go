@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestParseProtocol(t *testing.T) {
+	p, err := ParseProtocol("judgement.v2+json")
+	if err != nil || p.Version != ProtocolV2 || p.Encoding != "json" {
+		t.Fatalf("expected v2 json, got %+v (%v)", p, err)
+	}
+	if p.Name() != "judgement.v2+json" {
+		t.Errorf("expected name to round trip, got %s", p.Name())
+	}
+
+	for _, name := range []string{"chat", "judgement.v2", "judgement.two+json"} {
+		if _, err := ParseProtocol(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestNegotiateProtocol(t *testing.T) {
+	r := httptest.NewRequest("GET", "/ws", nil)
+	if p, err := negotiateProtocol(r, ""); err != nil || p != legacyProtocol {
+		t.Errorf("expected legacy protocol when none is offered, got %+v (%v)", p, err)
+	}
+
+	if p, err := negotiateProtocol(r, "judgement.v2+json"); err != nil || p.Version != ProtocolV2 {
+		t.Errorf("expected selected protocol, got %+v (%v)", p, err)
+	}
+
+	r.Header.Set("Sec-WebSocket-Protocol", "judgement.v9+json")
+	if _, err := negotiateProtocol(r, ""); err == nil {
+		t.Errorf("expected unsupported version to be rejected")
+	}
+}
+
+func TestWelcomeCompat(t *testing.T) {
+	env := types.Envelope{
+		Type:    types.MsgWelcome,
+		Payload: mustMarshal(Welcome{PlayerID: "a", ProtocolVersion: CurrentProtocol}),
+	}
+
+	var id types.PlayerID
+	legacy := legacyProtocol.outbound(env)
+	if err := json.Unmarshal(legacy.Payload, &id); err != nil || id != "a" {
+		t.Errorf("expected bare player id for v1, got %s", legacy.Payload)
+	}
+
+	refused := types.Envelope{Type: types.MsgError, Payload: mustMarshal(types.NewActionError(types.ErrNotYourTurn, "wait"))}
+	if got := legacyProtocol.outbound(refused); got.Type != legacyInvalidAction {
+		t.Errorf("expected invalid_action for v1, got %s", got.Type)
+	}
+
+	var welcome Welcome
+	current := Protocol{Version: ProtocolV2, Encoding: "json"}.outbound(env)
+	if err := json.Unmarshal(current.Payload, &welcome); err != nil || welcome.ProtocolVersion != ProtocolV2 {
+		t.Errorf("expected welcome with version for v2, got %s", current.Payload)
+	}
+}
+
+func TestLegacyCards(t *testing.T) {
+	v2 := Protocol{Version: ProtocolV2, Encoding: "json"}
+
+	played := types.Envelope{
+		Type:    types.MsgCardPlayed,
+		Payload: json.RawMessage(`{"player":"a","card":"SPADE-ACE","trumpSuit":"HEART"}`),
+	}
+	var legacy struct {
+		Card      legacyCard `json:"card"`
+		TrumpSuit int        `json:"trumpSuit"`
+	}
+	if err := json.Unmarshal(v2.outbound(played).Payload, &legacy); err != nil {
+		t.Fatalf("expected legacy card_played, got %v", err)
+	}
+	if legacy.Card != (legacyCard{Suit: 0, Rank: 14}) || legacy.TrumpSuit != 1 {
+		t.Errorf("expected numeric card and suit, got %+v", legacy)
+	}
+
+	turn := types.Envelope{
+		Type:    types.MsgTurnChanged,
+		Payload: json.RawMessage(`{"player":"a","state":"playing","legalCards":["SPADE-ACE","HEART-10"]}`),
+	}
+	var legacyTurn struct {
+		LegalCards []legacyCard `json:"legalCards"`
+	}
+	if err := json.Unmarshal(v2.outbound(turn).Payload, &legacyTurn); err != nil {
+		t.Fatalf("expected legacy turn_changed, got %v", err)
+	}
+	if len(legacyTurn.LegalCards) != 2 || legacyTurn.LegalCards[1] != (legacyCard{Suit: 1, Rank: 10}) {
+		t.Errorf("expected numeric legal cards, got %+v", legacyTurn.LegalCards)
+	}
+
+	current := Protocol{Version: CurrentProtocol, Encoding: "json"}
+	if got := current.outbound(played); string(got.Payload) != string(played.Payload) {
+		t.Errorf("expected current protocol to be untouched, got %s", got.Payload)
+	}
+
+	play := types.Envelope{Type: types.MsgPlayCard, Payload: json.RawMessage(`{"suit":1,"rank":10}`)}
+	if got := v2.inbound(play); string(got.Payload) != `"HEART-10"` {
+		t.Errorf("expected legacy card to be translated, got %s", got.Payload)
+	}
+
+	bad := types.Envelope{Type: types.MsgPlayCard, Payload: json.RawMessage(`{"suit":9,"rank":10}`)}
+	if got := v2.inbound(bad); validateInput(got) == nil {
+		t.Errorf("expected invalid legacy card to be rejected")
+	}
+}
+
+func TestLegacyStateSync(t *testing.T) {
+	session := NewSession("session", SessionAccess{})
+	defer session.cancel()
+
+	players := make(map[types.PlayerID]*types.Player)
+	for _, id := range []types.PlayerID{"a", "b"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		players[id] = &types.Player{ID: id, PlayerName: string(id), Send: make(chan types.Envelope, 256), Ctx: ctx, Cancel: cancel}
+		session.AddPlayer(players[id])
+	}
+	players["b"].Legacy = true
+
+	send := func(id types.PlayerID, msgType types.MessageType, payload any) {
+		handleIncomingMessage(session, players[id], types.Envelope{Type: msgType, Payload: mustMarshal(payload)})
+	}
+	// Messages up to and including the next one of the given type
+	until := func(id types.PlayerID, msgType types.MessageType) []types.Envelope {
+		var got []types.Envelope
+		for {
+			select {
+			case env := <-players[id].Send:
+				got = append(got, env)
+				if env.Type == msgType {
+					return got
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected %s for %s, got %v", msgType, id, got)
+			}
+		}
+	}
+
+	send("a", types.MsgSetReady, SetReady{Ready: true})
+	send("b", types.MsgSetReady, SetReady{Ready: true})
+	send("a", types.MsgStartGame, nil)
+	until("a", types.MsgStateSync)
+	sync := until("b", types.MsgStateSync)
+
+	var view g.PlayerView
+	if err := json.Unmarshal(sync[len(sync)-1].Payload, &view); err != nil {
+		t.Fatalf("expected a state_sync after the deal, got %v", err)
+	}
+
+	// Every move ends with a full state_sync for the v1 client only
+	send(view.TurnPlayer, types.MsgMakeBid, g.MakeBid{Bid: 0})
+	got := until("b", types.MsgStateSync)
+	if !slices.ContainsFunc(got, func(env types.Envelope) bool { return env.Type == types.MsgBidPlaced }) {
+		t.Errorf("expected the event before the state_sync, got %v", got)
+	}
+	for _, env := range until("a", types.MsgTurnChanged) {
+		if env.Type == types.MsgStateSync {
+			t.Errorf("expected the current client to follow events only")
+		}
+	}
+}
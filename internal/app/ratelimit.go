@@ -0,0 +1,48 @@
+package app
+
+import (
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Token bucket per player. Only touched from the session run loop.
+type RateLimiter struct {
+	rate    float64 // tokens refilled per second
+	burst   float64
+	buckets map[t.PlayerID]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[t.PlayerID]*bucket),
+	}
+}
+
+func (rl *RateLimiter) Allow(id t.PlayerID, now time.Time) bool {
+	b, ok := rl.buckets[id]
+	if !ok {
+		b = &bucket{tokens: rl.burst, last: now}
+		rl.buckets[id] = b
+	}
+
+	// Refill based on time since last call
+	b.tokens += now.Sub(b.last).Seconds() * rl.rate
+	if b.tokens > rl.burst {
+		b.tokens = rl.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(1, 3)
+	now := time.Now()
+
+	// Burst should be allowed
+	for i := 0; i < 3; i++ {
+		if !rl.Allow("a", now) {
+			t.Fatalf("expected message %d to be allowed", i)
+		}
+	}
+
+	if rl.Allow("a", now) {
+		t.Errorf("expected message to be rate limited after burst")
+	}
+
+	// Other players have their own bucket
+	if !rl.Allow("b", now) {
+		t.Errorf("expected other player to be allowed")
+	}
+
+	// One token refills after a second
+	now = now.Add(time.Second)
+	if !rl.Allow("a", now) {
+		t.Errorf("expected message to be allowed after refill")
+	}
+	if rl.Allow("a", now) {
+		t.Errorf("expected only one token to be refilled")
+	}
+}
@@ -0,0 +1,194 @@
+package app
+
+// Every message of the websocket protocol and the payload it carries.
+// Inbound payloads are checked here before they reach a handler or the Game,
+// and cmd/protogen generates the frontend types and JSON Schema from it.
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"  // FE -> BE
+	Outbound Direction = "outbound" // BE -> FE
+)
+
+type MessageSpec struct {
+	Type      t.MessageType
+	Direction Direction
+	Payload   reflect.Type // nil when the message carries no payload
+	Optional  bool         // payload may be left out, zero value is used
+}
+
+// Payloads that can check themselves beyond what the JSON shape allows
+type Validator interface {
+	Validate() error
+}
+
+func in(msgType t.MessageType, payload reflect.Type) MessageSpec {
+	return MessageSpec{Type: msgType, Direction: Inbound, Payload: payload}
+}
+
+func out(msgType t.MessageType, payload reflect.Type) MessageSpec {
+	return MessageSpec{Type: msgType, Direction: Outbound, Payload: payload}
+}
+
+func optional(spec MessageSpec) MessageSpec {
+	spec.Optional = true
+	return spec
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+var registry = indexMessages(
+	// FE -> BE
+	in(t.MsgStartGame, nil),
+	in(t.MsgConfigureGame, typeOf[g.Rules]()),
+	in(t.MsgKickPlayer, typeOf[TargetPlayer]()),
+	in(t.MsgTransferHost, typeOf[TargetPlayer]()),
+	in(t.MsgAddBot, nil),
+	in(t.MsgSetReady, typeOf[SetReady]()),
+	optional(in(t.MsgRematch, typeOf[Rematch]())),
+	in(t.MsgPauseGame, nil),
+	in(t.MsgResumeGame, nil),
+	optional(in(t.MsgAbandonGame, typeOf[AbandonVote]())),
+	in(t.MsgClaimSeat, typeOf[TargetPlayer]()),
+	in(t.MsgResume, typeOf[Resume]()),
+	in(t.MsgMakeBid, typeOf[g.MakeBid]()),
+	in(t.MsgPlayCard, typeOf[g.Card]()),
+	optional(in(t.MsgGetTrickHistory, typeOf[g.TrickHistoryRequest]())),
+	in(t.MsgUndoRequest, nil),
+	in(t.MsgUndoResponse, typeOf[g.UndoResponse]()),
+	in(t.MsgClaim, typeOf[g.Claim]()),
+	in(t.MsgClaimResponse, typeOf[g.ClaimResponse]()),
+	in(t.MsgChatSend, typeOf[ChatSend]()),
+	in(t.MsgMute, typeOf[MutePlayer]()),
+	in(t.MsgEmoteSend, typeOf[EmoteSend]()),
+
+	// BE -> FE
+	out(t.MsgWelcome, typeOf[Welcome]()),
+	out(t.MsgPlayersUpdate, typeOf[[]PlayerPublic]()),
+	out(t.MsgRulesUpdate, typeOf[g.Rules]()),
+	out(t.MsgGameStarted, nil),
+	out(t.MsgGameEnd, typeOf[g.GameEndPayload]()),
+	out(t.MsgSeriesUpdate, typeOf[Series]()),
+	out(t.MsgAbandonVote, typeOf[AbandonVoteStatus]()),
+	out(t.MsgSeatClaimed, typeOf[SeatClaimed]()),
+	out(t.MsgPlayerHand, typeOf[g.PlayerHand]()),
+	out(t.MsgStateSync, typeOf[g.PlayerView]()),
+	out(t.MsgRoundStarted, typeOf[g.RoundStarted]()),
+	out(t.MsgBidPlaced, typeOf[g.BidPlaced]()),
+	out(t.MsgCardPlayed, typeOf[g.CardPlayed]()),
+	out(t.MsgTrickWon, typeOf[g.TrickWon]()),
+	out(t.MsgRoundScored, typeOf[g.RoundScored]()),
+	out(t.MsgRoundSolved, typeOf[g.RoundSolved]()),
+	out(t.MsgTurnChanged, typeOf[g.TurnChanged]()),
+	out(t.MsgTrickHistory, typeOf[g.TrickHistory]()),
+	out(t.MsgUndoRequested, typeOf[g.UndoRequested]()),
+	out(t.MsgUndoResolved, typeOf[g.UndoResolved]()),
+	out(t.MsgClaimMade, typeOf[g.ClaimMade]()),
+	out(t.MsgClaimResolved, typeOf[g.ClaimResolved]()),
+	out(t.MsgBidAdvice, typeOf[g.BidAdvice]()),
+	out(t.MsgAck, typeOf[Ack]()),
+	out(t.MsgError, typeOf[ErrorPayload]()),
+	out(t.MsgChatMessage, typeOf[ChatMessage]()),
+	out(t.MsgChatHistory, typeOf[[]ChatMessage]()),
+	out(t.MsgEmote, typeOf[EmoteReaction]()),
+)
+
+// Known values of the string and number types used in payloads
+var enums = map[reflect.Type][]any{
+	typeOf[g.Suit]():          {g.Spade, g.Heart, g.Diamond, g.Club},
+	typeOf[g.Rank]():          {g.Two, g.Three, g.Four, g.Five, g.Six, g.Seven, g.Eight, g.Nine, g.Ten, g.Jack, g.Queen, g.King, g.Ace},
+	typeOf[g.State]():         {g.StateBid, g.StatePlay, g.StateResolution, g.StateGameOver},
+	typeOf[g.GameEndReason](): {g.GameCompleted, g.GameAbandoned},
+	typeOf[Emote]():           {EmoteNiceTrick, EmoteOuch, EmoteThumbsUp, EmoteWellBid, EmoteHurryUp, EmoteLaugh},
+	typeOf[t.ErrorCode](): {
+		t.ErrInvalidPayload, t.ErrUnknownMessage, t.ErrNotAllowed, t.ErrNotHost, t.ErrNotFound,
+		t.ErrRateLimited, t.ErrMuted, t.ErrNoGame, t.ErrGamePaused, t.ErrWrongPhase,
+		t.ErrNotSeated, t.ErrNotYourTurn, t.ErrMustFollowSuit, t.ErrCardNotInHand, t.ErrBidOutOfRange,
+	},
+}
+
+func indexMessages(specs ...MessageSpec) map[t.MessageType]MessageSpec {
+	index := make(map[t.MessageType]MessageSpec, len(specs))
+	for _, spec := range specs {
+		if _, dup := index[spec.Type]; dup {
+			panic("message registered twice: " + string(spec.Type))
+		}
+		index[spec.Type] = spec
+	}
+	return index
+}
+
+// All registered messages sorted by direction then type
+func Messages() []MessageSpec {
+	specs := make([]MessageSpec, 0, len(registry))
+	for _, spec := range registry {
+		specs = append(specs, spec)
+	}
+	slices.SortFunc(specs, func(a, b MessageSpec) int {
+		if a.Direction != b.Direction {
+			return strings.Compare(string(a.Direction), string(b.Direction))
+		}
+		return strings.Compare(string(a.Type), string(b.Type))
+	})
+	return specs
+}
+
+// Known values of an enum type, nil when the type is not an enum
+func EnumValues(typ reflect.Type) []any {
+	return enums[typ]
+}
+
+// Checks an inbound envelope against its registered payload type
+func validateInput(env t.Envelope) error {
+	spec, ok := registry[env.Type]
+	if !ok || spec.Direction != Inbound {
+		return t.NewActionError(t.ErrUnknownMessage, "Unknown message type "+string(env.Type))
+	}
+
+	payload := bytes.TrimSpace(env.Payload)
+	empty := len(payload) == 0 || bytes.Equal(payload, []byte("null"))
+
+	if spec.Payload == nil {
+		if !empty {
+			return t.NewActionError(t.ErrInvalidPayload, string(env.Type)+" does not take a payload")
+		}
+		return nil
+	}
+	if empty {
+		if spec.Optional {
+			return nil
+		}
+		return t.NewActionError(t.ErrInvalidPayload, string(env.Type)+" requires a payload")
+	}
+
+	value := reflect.New(spec.Payload)
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(value.Interface()); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid "+string(env.Type)+" payload: "+err.Error())
+	}
+	if dec.More() {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid "+string(env.Type)+" payload: trailing data")
+	}
+
+	if v, ok := value.Interface().(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return t.NewActionError(t.ErrInvalidPayload, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestValidateInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		msgType types.MessageType
+		payload string
+		code    types.ErrorCode // empty when valid
+	}{
+		{"no payload", types.MsgStartGame, "", ""},
+		{"unexpected payload", types.MsgStartGame, `{"x":1}`, types.ErrInvalidPayload},
+		{"valid payload", types.MsgMakeBid, `{"bid":2}`, ""},
+		{"missing payload", types.MsgMakeBid, "", types.ErrInvalidPayload},
+		{"unknown field", types.MsgMakeBid, `{"bid":2,"extra":true}`, types.ErrInvalidPayload},
+		{"wrong type", types.MsgMakeBid, `{"bid":"two"}`, types.ErrInvalidPayload},
+		{"trailing data", types.MsgMakeBid, `{"bid":2}{}`, types.ErrInvalidPayload},
+		{"optional payload", types.MsgRematch, "", ""},
+		{"long card", types.MsgPlayCard, `"SPADE-ACE"`, ""},
+		{"short card", types.MsgPlayCard, `"10h"`, ""},
+		{"invalid card", types.MsgPlayCard, `"1X"`, types.ErrInvalidPayload},
+		{"card object", types.MsgPlayCard, `{"suit":0,"rank":14}`, types.ErrInvalidPayload},
+		{"outbound type", types.MsgStateSync, "", types.ErrUnknownMessage},
+		{"unknown type", "bogus", "", types.ErrUnknownMessage},
+	}
+
+	for _, tt := range tests {
+		env := types.Envelope{Type: tt.msgType, Payload: json.RawMessage(tt.payload)}
+		err := validateInput(env)
+
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tt.name, err)
+			}
+			continue
+		}
+
+		var actionErr *types.ActionError
+		if !errors.As(err, &actionErr) || actionErr.Code != tt.code {
+			t.Errorf("%s: expected %s, got %v", tt.name, tt.code, err)
+		}
+	}
+}
+
+func TestMessagesSorted(t *testing.T) {
+	specs := Messages()
+	if len(specs) != len(registry) {
+		t.Fatalf("expected %d messages, got %d", len(registry), len(specs))
+	}
+	for i := 1; i < len(specs); i++ {
+		prev, cur := specs[i-1], specs[i]
+		if prev.Direction == cur.Direction && prev.Type >= cur.Type {
+			t.Errorf("expected %s before %s", prev.Type, cur.Type)
+		}
+	}
+}
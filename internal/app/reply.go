@@ -0,0 +1,121 @@
+package app
+
+// Acks and errors sent in reply to client inputs
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const maxCachedReplies = 64 // per player
+
+type Ack struct {
+	ID string `json:"id"`
+}
+
+type ErrorPayload struct {
+	ID      string      `json:"id,omitempty"`
+	Code    t.ErrorCode `json:"code"`
+	Message string      `json:"message"`
+}
+
+// Every error is sent back, acks are only sent when the client asked with an id
+func replyFor(id string, err error) (t.Envelope, bool) {
+	if err == nil {
+		if id == "" {
+			return t.Envelope{}, false
+		}
+		return t.Envelope{Type: t.MsgAck, Payload: mustMarshal(Ack{ID: id})}, true
+	}
+
+	var actionErr *t.ActionError
+	if !errors.As(err, &actionErr) {
+		log.Printf("Unexpected input error: %v", err)
+		actionErr = t.NewActionError(t.ErrNotAllowed, err.Error())
+	}
+
+	return t.Envelope{
+		Type: t.MsgError,
+		Payload: mustMarshal(ErrorPayload{
+			ID:      id,
+			Code:    actionErr.Code,
+			Message: actionErr.Message,
+		}),
+	}, true
+}
+
+func (s *Session) sendReply(id t.PlayerID, reply t.Envelope) {
+	s.Emit(t.GameOutput{
+		Players: []t.PlayerID{id},
+		Env:     reply,
+	})
+}
+
+// Remembers the latest acks of each connection so retried inputs are not applied twice
+type ReplyCache struct {
+	size    int
+	players map[t.PlayerID]*playerReplies // keyed by connection
+	mu      sync.Mutex
+}
+
+type playerReplies struct {
+	order   []string // oldest first
+	replies map[string]t.Envelope
+}
+
+func NewReplyCache(size int) *ReplyCache {
+	return &ReplyCache{
+		size:    size,
+		players: make(map[t.PlayerID]*playerReplies),
+	}
+}
+
+func (rc *ReplyCache) Lookup(player t.PlayerID, id string) (t.Envelope, bool) {
+	if id == "" {
+		return t.Envelope{}, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	pr, ok := rc.players[player]
+	if !ok {
+		return t.Envelope{}, false
+	}
+	reply, ok := pr.replies[id]
+	return reply, ok
+}
+
+func (rc *ReplyCache) Store(player t.PlayerID, id string, reply t.Envelope) {
+	if id == "" {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	pr, ok := rc.players[player]
+	if !ok {
+		pr = &playerReplies{replies: make(map[string]t.Envelope)}
+		rc.players[player] = pr
+	}
+
+	if _, exists := pr.replies[id]; !exists {
+		pr.order = append(pr.order, id)
+	}
+	pr.replies[id] = reply
+
+	// Evict the oldest once full
+	for len(pr.order) > rc.size {
+		delete(pr.replies, pr.order[0])
+		pr.order = pr.order[1:]
+	}
+}
+
+// Drops the replies of a connection that is gone
+func (rc *ReplyCache) Forget(player t.PlayerID) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.players, player)
+}
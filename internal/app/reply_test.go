@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestReplyCache(t *testing.T) {
+	rc := NewReplyCache(2)
+	ack := types.Envelope{Type: types.MsgAck}
+
+	rc.Store("a", "1", ack)
+	rc.Store("a", "2", ack)
+
+	if _, ok := rc.Lookup("a", "1"); !ok {
+		t.Errorf("expected reply for id 1")
+	}
+	if _, ok := rc.Lookup("b", "1"); ok {
+		t.Errorf("expected replies to be kept per player")
+	}
+
+	// Oldest reply is evicted once full
+	rc.Store("a", "3", ack)
+	if _, ok := rc.Lookup("a", "1"); ok {
+		t.Errorf("expected id 1 to be evicted")
+	}
+	if _, ok := rc.Lookup("a", "3"); !ok {
+		t.Errorf("expected reply for id 3")
+	}
+
+	// Inputs without an id are never cached
+	rc.Store("a", "", ack)
+	if _, ok := rc.Lookup("a", ""); ok {
+		t.Errorf("expected no reply for empty id")
+	}
+
+	rc.Forget("a")
+	if _, ok := rc.Lookup("a", "3"); ok {
+		t.Errorf("expected replies to go with the connection")
+	}
+}
+
+func TestReplyFor(t *testing.T) {
+	if _, ok := replyFor("", nil); ok {
+		t.Errorf("expected no ack without an id")
+	}
+
+	reply, ok := replyFor("7", types.NewActionError(types.ErrNotYourTurn, "wait"))
+	if !ok || reply.Type != types.MsgError {
+		t.Fatalf("expected error reply, got %+v", reply)
+	}
+}
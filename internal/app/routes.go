@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/json"
+	"expvar"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -11,6 +12,7 @@ import (
 
 func (a *App) RegisterRoutes() http.Handler {
 	r := chi.NewRouter()
+	r.Use(redactSecrets)
 	r.Use(middleware.Logger)
 
 	r.Use(cors.Handler(cors.Options{
@@ -25,6 +27,11 @@ func (a *App) RegisterRoutes() http.Handler {
 		r.Get("/health", a.HealthHandler)
 		r.Post("/session", a.CreateSessionHandler)
 		r.Get("/session/{sessionId}", a.GetSessionHandler)
+		r.Post("/session/{sessionId}/invite", a.CreateInviteHandler)
+		r.Get("/sessions", a.ListSessionsHandler)
+		r.Get("/games/{gameId}", a.GetGameHandler)
+		r.Get("/games/{gameId}/double-dummy", a.GetDoubleDummyHandler)
+		r.Get("/games/{gameId}/review", a.GetReviewHandler)
 	})
 
 	r.Get("/ws", a.wsHandler)
@@ -32,6 +39,37 @@ func (a *App) RegisterRoutes() http.Handler {
 	return r
 }
 
+// Query parameters that grant access to a private session
+var secretParams = []string{"passcode", "invite"}
+
+// Browsers can't set headers on a websocket upgrade, so join secrets come in
+// the query. The logger prints RequestURI, handlers read URL, so only the
+// former is redacted.
+func redactSecrets(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		redacted := false
+		for _, key := range secretParams {
+			if query.Has(key) {
+				query.Set(key, "redacted")
+				redacted = true
+			}
+		}
+		if redacted {
+			r = r.Clone(r.Context())
+			r.RequestURI = r.URL.Path + "?" + query.Encode()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Operational endpoints, only served on the admin listener
+func (a *App) RegisterAdminRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/debug/vars", expvar.Handler().ServeHTTP)
+	return r
+}
+
 func (a *App) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
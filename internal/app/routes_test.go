@@ -1,6 +1,7 @@
 package app
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -37,3 +38,94 @@ func TestHandler(t *testing.T) {
 		t.Errorf("expected response body to be %v; got %v", expected, string(body))
 	}
 }
+
+func TestListSessionsHandler(t *testing.T) {
+	app := NewApp()
+	app.sessionStore.GenerateRandomSession(SessionAccess{})
+	app.sessionStore.GenerateRandomSession(SessionAccess{})
+
+	server := httptest.NewServer(app.RegisterRoutes())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/sessions?open=true&state=lobby")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK; got %v", resp.Status)
+	}
+
+	var infos []SessionInfo
+	if err := json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 sessions; got %d", len(infos))
+	}
+	for _, info := range infos {
+		if info.State != SessionLobby || info.Seats.Open != info.Rules.MaxPlayers {
+			t.Errorf("unexpected session info: %+v", info)
+		}
+	}
+
+	// Invalid filters are rejected
+	resp, err = http.Get(server.URL + "/api/sessions?state=nope")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status Bad Request; got %v", resp.Status)
+	}
+}
+
+func TestMetricsOnlyOnAdminRoutes(t *testing.T) {
+	app := NewApp()
+
+	public := httptest.NewServer(app.RegisterRoutes())
+	defer public.Close()
+	admin := httptest.NewServer(app.RegisterAdminRoutes())
+	defer admin.Close()
+
+	resp, err := http.Get(public.URL + "/debug/vars")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected metrics to be hidden on the public routes; got %v", resp.Status)
+	}
+
+	resp, err = http.Get(admin.URL + "/debug/vars")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status OK; got %v", resp.Status)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	var logged, passcode string
+	handler := redactSecrets(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logged = r.RequestURI
+		passcode = r.URL.Query().Get("passcode")
+	}))
+
+	r := httptest.NewRequest("GET", "/ws?sessionId=abc&passcode=hunter2&invite=token", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if strings.Contains(logged, "hunter2") || strings.Contains(logged, "token") {
+		t.Errorf("expected secrets to be kept out of the request uri, got %s", logged)
+	}
+	if !strings.Contains(logged, "sessionId=abc") {
+		t.Errorf("expected other parameters to be kept, got %s", logged)
+	}
+	if passcode != "hunter2" {
+		t.Errorf("expected the handler to still see the passcode, got %q", passcode)
+	}
+}
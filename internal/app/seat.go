@@ -0,0 +1,106 @@
+package app
+
+// Late joiners taking over a seat that was left mid-game or played by a bot
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type SeatClaimed struct {
+	SeatID     t.PlayerID `json:"seatId"`
+	PlayerName string     `json:"playerName"`
+	PreviousID t.PlayerID `json:"previousId"` // id the claimer joined with
+}
+
+func (s *Session) handleClaimSeat(input t.GameInput) error {
+	if !s.gameInProgress() {
+		return t.NewActionError(t.ErrNoGame, "No game in progress")
+	}
+	if _, seated := s.game.Players[input.Player.ID]; seated {
+		return t.NewActionError(t.ErrNotAllowed, "You already have a seat")
+	}
+
+	var payload TargetPlayer
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid seat request")
+	}
+	if _, ok := s.game.Players[payload.PlayerID]; !ok {
+		return t.NewActionError(t.ErrNotFound, "Seat not found")
+	}
+
+	player := input.Player
+	previousID := player.ID
+
+	if !s.takeSeat(player, payload.PlayerID) {
+		return t.NewActionError(t.ErrNotAllowed, "Seat is taken")
+	}
+
+	log.Printf("Player (%v) took over seat (%v) in session (%v)\n", player.PlayerName, payload.PlayerID, s.ID)
+
+	s.game.HandOverSeat(payload.PlayerID, player.PlayerName)
+
+	sendWelcome(player, s)
+	s.game.SyncPlayer(payload.PlayerID)
+
+	s.Emit(t.GameOutput{
+		Players: s.allPlayerIDs(),
+		Env: t.Envelope{
+			Type: t.MsgSeatClaimed,
+			Payload: mustMarshal(SeatClaimed{
+				SeatID:     payload.PlayerID,
+				PlayerName: player.PlayerName,
+				PreviousID: previousID,
+			}),
+		},
+	})
+	broadcastPlayersUpdate(s)
+	return nil
+}
+
+// Files the connection under the seat id so the game sees the original player.
+// The connection itself keeps its id.
+func (s *Session) takeSeat(player *t.Player, seatID t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, connected := s.players[seatID]; connected {
+		return false
+	}
+	_, bot := s.bots[seatID]
+	delete(s.bots, seatID)
+
+	oldID := player.ID
+	delete(s.players, oldID)
+	delete(s.ready, oldID)
+
+	s.seatOf[player.ID] = seatID
+	s.players[seatID] = player
+
+	// Keep numbering where this connection left off, the seat is resynced anyway
+	if stream, ok := s.streams[oldID]; ok {
+		s.streams[seatID] = stream
+		delete(s.streams, oldID)
+	}
+	s.seated[seatID] = player.PlayerName
+
+	// A bot's seat never left the order, a vacated one takes the joiner's place
+	if bot {
+		s.order = slices.DeleteFunc(s.order, func(id t.PlayerID) bool {
+			return id == oldID
+		})
+	} else {
+		for i, id := range s.order {
+			if id == oldID {
+				s.order[i] = seatID
+			}
+		}
+	}
+	if s.host == oldID {
+		s.host = seatID
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package app
+
+// Rematches within a session and the running series score
+
+import (
+	"encoding/json"
+	"slices"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type Rematch struct {
+	RotateDealer bool `json:"rotateDealer"`
+}
+
+// Scores carried across consecutive games in a session
+type Series struct {
+	Games  int                    `json:"games"`
+	Totals map[t.PlayerID]g.Score `json:"totals"`
+	Wins   map[t.PlayerID]int     `json:"wins"`
+}
+
+func NewSeries() *Series {
+	return &Series{
+		Totals: make(map[t.PlayerID]g.Score),
+		Wins:   make(map[t.PlayerID]int),
+	}
+}
+
+func (sr *Series) Record(scores map[t.PlayerID]g.Score) {
+	sr.Games++
+
+	var best g.Score
+	for id, score := range scores {
+		sr.Totals[id] += score
+		best = max(best, score)
+	}
+
+	// Ties share the win
+	for id, score := range scores {
+		if score == best {
+			sr.Wins[id]++
+		}
+	}
+}
+
+func (s *Session) finishGame() {
+	s.setState(SessionFinished)
+	s.series.Record(s.game.Totals())
+	if s.archive != nil {
+		s.archive.Add(s.game.Record())
+	}
+
+	s.Emit(t.GameOutput{
+		Players: s.allPlayerIDs(),
+		Env: t.Envelope{
+			Type:    t.MsgSeriesUpdate,
+			Payload: mustMarshal(s.series),
+		},
+	})
+}
+
+func (s *Session) handleRematch(input t.GameInput) error {
+	if err := s.requireHost(input.Player.ID, "start a rematch"); err != nil {
+		return err
+	}
+	if s.game == nil || !s.game.IsOver() {
+		return t.NewActionError(t.ErrWrongPhase, "A rematch can only start once the game is over")
+	}
+
+	var payload Rematch
+	if len(input.Env.Payload) > 0 {
+		if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+			return t.NewActionError(t.ErrInvalidPayload, "Invalid rematch request")
+		}
+	}
+
+	// The players of the last game still here play again, in the same seats
+	seats := slices.DeleteFunc(s.game.Seats(), func(id t.PlayerID) bool {
+		return !s.hasMember(id)
+	})
+	if err := s.checkCanStart(seats); err != nil {
+		return t.NewActionError(t.ErrNotAllowed, err.Error())
+	}
+
+	// Keep the same dealer unless asked to rotate. NewGame picks one if they left.
+	dealer := s.game.Dealer()
+	if payload.RotateDealer {
+		dealer = seatAfter(s.game.Seats(), seats, dealer)
+	}
+
+	s.startGame(seats, dealer)
+	return nil
+}
+
+// First player after id in the old seating order who is still seated
+func seatAfter(order, seats []t.PlayerID, id t.PlayerID) t.PlayerID {
+	start := slices.Index(order, id)
+	for i := 1; i <= len(order); i++ {
+		next := order[(start+i)%len(order)]
+		if slices.Contains(seats, next) {
+			return next
+		}
+	}
+	return ""
+}
@@ -2,11 +2,17 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"log"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 
 	g "github.com/B33Boy/Judgement/internal/game"
 	t "github.com/B33Boy/Judgement/internal/types"
+	"github.com/coder/websocket"
 )
 
 // Implement SessionView implicitly
@@ -14,55 +20,148 @@ func (s *Session) Context() context.Context {
 	return s.ctx
 }
 
+// Connected players and bots, bots have no connection
 func (s *Session) GetPlayers() map[t.PlayerID]*t.Player {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.players
+
+	players := maps.Clone(s.players)
+	for id, name := range s.bots {
+		players[id] = &t.Player{ID: id, PlayerName: name, Bot: true}
+	}
+	return players
 }
 
+// Routes straight to the players' send channels. Most outputs come from the
+// run loop itself, so queueing them for the run loop could deadlock.
 func (s *Session) Emit(out t.GameOutput) {
-	select {
-	case s.outputs <- out:
-	case <-s.ctx.Done():
+	if s.ctx.Err() != nil {
+		return
 	}
+	s.handleOutput(out)
+}
+
+// A passcode makes the session private even without Private set
+type SessionAccess struct {
+	Private  bool
+	Passcode string
 }
 
 type Session struct {
-	ID      string `json:"sessionId"`
-	players map[t.PlayerID]*t.Player
+	ID      string                   `json:"sessionId"`
+	players map[t.PlayerID]*t.Player // keyed by the seat a connection took over, if any
+	seatOf  map[t.PlayerID]t.PlayerID
+
+	// Private sessions need a passcode or invite token to join
+	private      bool
+	passcodeHash []byte
 
-	inputs  chan t.GameInput
-	outputs chan t.GameOutput
+	inputs chan t.GameInput
 
 	game *g.Game
 
+	// Lobby
+	host  t.PlayerID
+	order []t.PlayerID          // join order, used for host hand-off
+	bots  map[t.PlayerID]string // seats played by the server, id to name
+	rules g.Rules
+	ready map[t.PlayerID]bool
+
+	series       *Series
+	abandonVotes map[t.PlayerID]bool // dropped when the voter leaves
+	archive      *GameArchive        // where finished games go, nil keeps none
+
+	replies *ReplyCache
+	streams map[t.PlayerID]*outStream
+	resync  map[t.PlayerID]bool // v1 clients sent game events since their last state_sync
+
+	// Metadata
+	state     SessionState
+	seated    map[t.PlayerID]string // seat id to the name it was taken under
+	createdAt time.Time
+
+	// Lifecycle
+	joined       bool // at least one player has joined
+	lastActivity time.Time
+
+	// Chat
+	muted       map[t.PlayerID]bool
+	chatHistory []ChatMessage
+	chatLimiter *RateLimiter
+
+	emoteLimiter *RateLimiter
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	mu sync.Mutex
 }
 
-func NewSession(sessionId string) *Session {
+func NewSession(sessionId string, access SessionAccess) *Session {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	s := &Session{
 		ID:      sessionId,
 		players: make(map[t.PlayerID]*t.Player),
+		seatOf:  make(map[t.PlayerID]t.PlayerID),
+
+		private: access.Private || access.Passcode != "",
 
-		inputs:  make(chan t.GameInput, 32),
-		outputs: make(chan t.GameOutput, 32),
+		inputs: make(chan t.GameInput, 32),
+
+		game: nil,
+
+		order: make([]t.PlayerID, 0),
+		bots:  make(map[t.PlayerID]string),
+		rules: g.DefaultRules(),
+		ready: make(map[t.PlayerID]bool),
+
+		series:       NewSeries(),
+		abandonVotes: make(map[t.PlayerID]bool),
+
+		replies: NewReplyCache(maxCachedReplies),
+		streams: make(map[t.PlayerID]*outStream),
+		resync:  make(map[t.PlayerID]bool),
+
+		state:     SessionLobby,
+		seated:    make(map[t.PlayerID]string),
+		createdAt: time.Now(),
+
+		lastActivity: time.Now(),
+
+		muted:       make(map[t.PlayerID]bool),
+		chatHistory: make([]ChatMessage, 0, maxChatHistory),
+		chatLimiter: NewRateLimiter(chatRate, chatBurst),
+
+		emoteLimiter: NewRateLimiter(emoteRate, emoteBurst),
 
-		game:   nil,
 		ctx:    ctx,
 		cancel: cancel,
 	}
 
+	if access.Passcode != "" {
+		hash := sha256.Sum256([]byte(access.Passcode))
+		s.passcodeHash = hash[:]
+	}
+
 	go s.run()
 
 	return s
 }
 
+func (s *Session) IsPrivate() bool {
+	return s.private
+}
+
+func (s *Session) CheckPasscode(passcode string) bool {
+	if s.passcodeHash == nil || passcode == "" {
+		return false
+	}
+	hash := sha256.Sum256([]byte(passcode))
+	return subtle.ConstantTimeCompare(hash[:], s.passcodeHash) == 1
+}
+
 func (s *Session) AddPlayer(player *t.Player) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -71,19 +170,55 @@ func (s *Session) AddPlayer(player *t.Player) {
 	if old, ok := s.players[player.ID]; ok {
 		old.Cancel()
 		close(old.Send)
+	} else {
+		s.order = append(s.order, player.ID)
 	}
 
 	s.players[player.ID] = player
+	s.joined = true
+	s.lastActivity = time.Now()
+
+	// First player to join hosts the session
+	if s.host == "" {
+		s.host = player.ID
+	}
 }
 
 func (s *Session) RemovePlayer(player *t.Player) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if player, ok := s.players[player.ID]; ok {
+	id := s.memberIDLocked(player)
+	delete(s.seatOf, player.ID)
+	s.replies.Forget(player.ID)
+
+	// Someone else may have taken the seat back in the meantime
+	if current, ok := s.players[id]; ok && current == player {
 		player.Cancel()    // stop the write loop
 		close(player.Send) // close outbound channel
-		delete(s.players, player.ID)
+		delete(s.players, id)
+		delete(s.ready, id)
+		delete(s.abandonVotes, id)
+		delete(s.resync, id)
+		if _, seated := s.seated[id]; !seated {
+			delete(s.streams, id)
+		}
+		s.lastActivity = time.Now()
+		s.order = slices.DeleteFunc(s.order, func(member t.PlayerID) bool {
+			return member == id
+		})
+
+		// Hand off to the longest connected player, bots can't host
+		if s.host == id {
+			s.host = ""
+			for _, member := range s.order {
+				if _, connected := s.players[member]; connected {
+					s.host = member
+					log.Printf("Host of session (%v) passed to %v", s.ID, s.host)
+					break
+				}
+			}
+		}
 
 		if len(s.players) == 0 {
 			s.cancel()
@@ -91,45 +226,205 @@ func (s *Session) RemovePlayer(player *t.Player) {
 	}
 }
 
-func (s *Session) CopyPlayerList() []*t.Player {
+// Id the session and game know a connection by, the seat it took over if any.
+// The connection keeps its own id, the read and write loops use it unlocked.
+func (s *Session) memberID(player *t.Player) t.PlayerID {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.memberIDLocked(player)
+}
 
-	players := make([]*t.Player, 0, len(s.players))
-	for _, p := range s.players {
-		players = append(players, p)
+func (s *Session) memberIDLocked(player *t.Player) t.PlayerID {
+	if seat, ok := s.seatOf[player.ID]; ok {
+		return seat
 	}
-	return players
+	return player.ID
+}
+
+// Connection as seen by the session and game, under its seat id
+func (s *Session) asMember(player *t.Player) *t.Player {
+	id := s.memberID(player)
+	if id == player.ID {
+		return player
+	}
+	member := *player
+	member.ID = id
+	return &member
+}
+
+func (s *Session) PublicPlayers() []PlayerPublic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	public := make([]PlayerPublic, 0, len(s.order))
+	for _, id := range s.order {
+		_, bot := s.bots[id]
+		public = append(public, PlayerPublic{
+			ID:     id,
+			Name:   s.nameLocked(id),
+			IsHost: id == s.host,
+			Ready:  s.ready[id] || bot,
+			IsBot:  bot,
+		})
+	}
+	return public
+}
+
+func (s *Session) Seats() []t.PlayerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.order)
+}
+
+func (s *Session) IsReady(id t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ready[id]
+}
+
+func (s *Session) Host() t.PlayerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.host
+}
+
+func (s *Session) isHost(id t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.host == id
+}
+
+// Whether a connection holds the seat, for votes that only wait on those
+func (s *Session) IsConnected(id t.PlayerID) bool {
+	return s.hasPlayer(id)
+}
+
+func (s *Session) hasPlayer(id t.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.players[id]
+	return ok
+}
+
+func (s *Session) allPlayerIDs() []t.PlayerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]t.PlayerID, 0, len(s.players))
+	for id := range s.players {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 func (s *Session) run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 
+		case now := <-ticker.C:
+			s.tick(now)
+			s.syncLegacyPlayers()
+
 		case input := <-s.inputs:
 			s.handleInput(input)
-
-		case output := <-s.outputs:
-			s.handleOutput(output)
+			s.syncLegacyPlayers()
 		}
 	}
 }
 
+func (s *Session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = time.Now()
+}
+
+// Disconnects everyone and stops the run loop
+func (s *Session) Close(reason string) {
+	s.mu.Lock()
+	players := make([]*t.Player, 0, len(s.players))
+	for _, p := range s.players {
+		players = append(players, p)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+
+	for _, p := range players {
+		go p.Conn.Close(websocket.StatusGoingAway, reason)
+	}
+}
+
 func (s *Session) handleInput(input t.GameInput) {
+	s.touch()
+	conn := input.Player.ID
+	input.Player = s.asMember(input.Player)
+
+	// Retried inputs get the original ack instead of being applied twice
+	if reply, ok := s.replies.Lookup(conn, input.Env.ID); ok {
+		s.sendReply(input.Player.ID, reply)
+		return
+	}
+
+	err := validateInput(input.Env)
+	if err == nil {
+		err = s.dispatch(input)
+	}
+
+	if reply, ok := replyFor(input.Env.ID, err); ok {
+		// Rejected inputs changed nothing, a corrected retry under the same id is applied
+		if err == nil {
+			s.replies.Store(conn, input.Env.ID, reply)
+		}
+		s.sendReply(input.Player.ID, reply)
+	}
+}
+
+func (s *Session) dispatch(input t.GameInput) error {
 	switch input.Env.Type {
 	case t.MsgStartGame:
-		if s.game != nil {
-			return // already started
-		}
-		s.game = g.NewGame(s)
-		s.game.Start()
+		return s.handleStartGame(input)
+	case t.MsgConfigureGame:
+		return s.handleConfigureGame(input)
+	case t.MsgKickPlayer:
+		return s.handleKickPlayer(input)
+	case t.MsgTransferHost:
+		return s.handleTransferHost(input)
+	case t.MsgAddBot:
+		return s.handleAddBot(input)
+	case t.MsgSetReady:
+		return s.handleSetReady(input)
+	case t.MsgRematch:
+		return s.handleRematch(input)
+	case t.MsgPauseGame:
+		return s.handlePauseGame(input)
+	case t.MsgResumeGame:
+		return s.handleResumeGame(input)
+	case t.MsgAbandonGame:
+		return s.handleAbandonVote(input)
+	case t.MsgClaimSeat:
+		return s.handleClaimSeat(input)
+	case t.MsgChatSend:
+		return s.handleChat(input)
+	case t.MsgMute:
+		return s.handleMute(input)
+	case t.MsgEmoteSend:
+		return s.handleEmote(input)
+	case t.MsgResume:
+		return s.handleResume(input)
 	default:
-		if s.game == nil {
-			return
+		if !s.gameInProgress() {
+			return t.NewActionError(t.ErrNoGame, "No game in progress")
+		}
+		err := s.game.HandleGameInput(input)
+		if s.game.IsOver() {
+			s.finishGame()
 		}
-		s.game.HandleGameInput(input)
+		return err
 	}
 }
 
@@ -139,16 +434,6 @@ func (s *Session) handleOutput(output t.GameOutput) {
 
 	// Route outputs to the specific players given in output
 	for _, id := range output.Players {
-
-		// Get Player from id common to Player and GamePlayer
-		player := s.players[id]
-
-		select {
-		case player.Send <- output.Env:
-			// success
-		default:
-			// slow client, drop or disconnect
-			log.Println("Dropping message for slow player with ID:", id)
-		}
+		s.deliverLocked(id, output.Env)
 	}
 }
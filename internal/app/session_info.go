@@ -0,0 +1,118 @@
+package app
+
+// Public session metadata for the lobby listing
+
+import (
+	"time"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type SessionState string
+
+const (
+	SessionLobby    SessionState = "lobby"
+	SessionInGame   SessionState = "in_game"
+	SessionFinished SessionState = "finished"
+)
+
+type SeatInfo struct {
+	Taken int `json:"taken"`
+	Max   int `json:"max"`
+	Open  int `json:"open"`
+}
+
+type SessionInfo struct {
+	SessionID  string        `json:"sessionId"`
+	Private    bool          `json:"private"`
+	State      SessionState  `json:"state"`
+	Seats      SeatInfo      `json:"seats"`
+	Host       *PlayerPublic `json:"host"`
+	Rules      g.Rules       `json:"rules"`
+	Spectators int           `json:"spectators"`
+	CreatedAt  time.Time     `json:"createdAt"`
+
+	VacantSeats []PlayerPublic `json:"vacantSeats,omitempty"` // seats that can be claimed mid-game
+}
+
+func (s *Session) Info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Seats are fixed once a game starts, everyone else is watching
+	// unless they claim a seat someone left or a bot plays
+	taken := len(s.players) + len(s.bots)
+	open := max(s.rules.MaxPlayers-taken, 0)
+	spectators := 0
+	var vacant []PlayerPublic
+	if s.state != SessionLobby {
+		for id := range s.players {
+			if _, ok := s.seated[id]; !ok {
+				spectators++
+			}
+		}
+		if s.state == SessionInGame {
+			vacant = s.vacantSeatsLocked()
+		}
+		taken = len(s.seated) - len(vacant)
+		open = len(vacant)
+	}
+
+	var host *PlayerPublic
+	if player, ok := s.players[s.host]; ok {
+		host = &PlayerPublic{
+			ID:     s.host,
+			Name:   player.PlayerName,
+			IsHost: true,
+			Ready:  s.ready[s.host],
+		}
+	}
+
+	return SessionInfo{
+		SessionID: s.ID,
+		Private:   s.private,
+		State:     s.state,
+		Seats: SeatInfo{
+			Taken: taken,
+			Max:   s.rules.MaxPlayers,
+			Open:  open,
+		},
+		Host:       host,
+		Rules:      s.rules,
+		Spectators: spectators,
+		CreatedAt:  s.createdAt,
+
+		VacantSeats: vacant,
+	}
+}
+
+// Seats in the current game whose player has disconnected or is a bot
+func (s *Session) vacantSeatsLocked() []PlayerPublic {
+	vacant := make([]PlayerPublic, 0)
+	for id, name := range s.seated {
+		if _, bot := s.bots[id]; bot {
+			vacant = append(vacant, PlayerPublic{ID: id, Name: name, IsBot: true})
+		} else if _, connected := s.players[id]; !connected {
+			vacant = append(vacant, PlayerPublic{ID: id, Name: name})
+		}
+	}
+	return vacant
+}
+
+func (s *Session) setState(state SessionState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+// Record who holds a seat in the current game
+func (s *Session) seatPlayers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seated = make(map[t.PlayerID]string, len(s.game.Players))
+	for id, player := range s.game.Players {
+		s.seated[id] = player.PlayerName
+	}
+}
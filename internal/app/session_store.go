@@ -1,18 +1,24 @@
 package app
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"log"
 	"sync"
 )
 
+// No 0/o, 1/l/i so ids can be read out loud and typed without mistakes
+const sessionIdAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
 type SessionStore struct {
 	sessions map[string]*Session
+	archive  *GameArchive // finished games of every session
 	mu       sync.RWMutex
 }
 
 func NewSessionStore() *SessionStore {
 	return &SessionStore{
 		sessions: make(map[string]*Session),
+		archive:  NewGameArchive(maxArchivedGames),
 	}
 }
 
@@ -23,13 +29,28 @@ func (s *SessionStore) GetSession(sessionId string) (*Session, bool) {
 	return session, exists
 }
 
+func (s *SessionStore) ListSessions() []*Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
 func (s *SessionStore) DeleteSession(sessionId string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.sessions, sessionId)
+
+	if _, exists := s.sessions[sessionId]; exists {
+		delete(s.sessions, sessionId)
+		sessionsActive.Add(-1)
+	}
 }
 
-func (s *SessionStore) GenerateRandomSession() *Session {
+func (s *SessionStore) GenerateRandomSession(access SessionAccess) *Session {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -43,17 +64,31 @@ func (s *SessionStore) GenerateRandomSession() *Session {
 		}
 	}
 
-	session := NewSession(id)
+	session := NewSession(id, access)
+	session.archive = s.archive
 	s.sessions[id] = session
+
+	sessionsCreated.Add(1)
+	sessionsActive.Add(1)
+	log.Printf("Session (%v) created", id)
+
 	return session
 }
 
 func randomString(n int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyz"
+	// Reject bytes past the largest multiple of the alphabet size to avoid modulo bias
+	limit := byte(256 - 256%len(sessionIdAlphabet))
 
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+	b := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(b) < n {
+		rand.Read(buf)
+		for _, r := range buf {
+			if r >= limit || len(b) == n {
+				continue
+			}
+			b = append(b, sessionIdAlphabet[int(r)%len(sessionIdAlphabet)])
+		}
 	}
 	return string(b)
 }
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRandomString(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		id := randomString(8)
+		if len(id) != 8 {
+			t.Fatalf("expected id of length 8, got %q", id)
+		}
+		for _, r := range id {
+			if !strings.ContainsRune(sessionIdAlphabet, r) {
+				t.Fatalf("id %q contains character outside the alphabet", id)
+			}
+		}
+	}
+}
+
+func TestPrivateSessionPasscode(t *testing.T) {
+	store := NewSessionStore()
+	session := store.GenerateRandomSession(SessionAccess{Private: true, Passcode: "hunter2"})
+	defer session.cancel()
+
+	if !session.IsPrivate() {
+		t.Fatalf("expected session to be private")
+	}
+	if !session.CheckPasscode("hunter2") {
+		t.Errorf("expected passcode to match")
+	}
+	if session.CheckPasscode("hunter3") || session.CheckPasscode("") {
+		t.Errorf("expected wrong passcode to be rejected")
+	}
+
+	// Setting a passcode alone still keeps strangers out
+	session = store.GenerateRandomSession(SessionAccess{Passcode: "hunter2"})
+	defer session.cancel()
+
+	if !session.IsPrivate() {
+		t.Errorf("expected a passcode to make the session private")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	store := NewSessionStore()
+	cfg := DefaultJanitorConfig()
+
+	unjoined := store.GenerateRandomSession(SessionAccess{})
+	cancelled := store.GenerateRandomSession(SessionAccess{})
+	active := store.GenerateRandomSession(SessionAccess{})
+	defer active.cancel()
+
+	cancelled.cancel()
+	active.mu.Lock()
+	active.joined = true
+	active.mu.Unlock()
+
+	// Nothing has expired yet except the cancelled session
+	reaped := store.Sweep(time.Now(), cfg)
+	if len(reaped) != 1 || reaped[cancelled.ID] != ReapCancelled {
+		t.Fatalf("expected only cancelled session to be reaped, got %v", reaped)
+	}
+
+	reaped = store.Sweep(time.Now().Add(cfg.UnjoinedTTL+time.Second), cfg)
+	if len(reaped) != 1 || reaped[unjoined.ID] != ReapUnjoined {
+		t.Fatalf("expected unjoined session to be reaped, got %v", reaped)
+	}
+	if unjoined.ctx.Err() == nil {
+		t.Errorf("expected reaped session to be closed")
+	}
+
+	reaped = store.Sweep(time.Now().Add(cfg.IdleTTL+time.Second), cfg)
+	if len(reaped) != 1 || reaped[active.ID] != ReapIdle {
+		t.Fatalf("expected idle session to be reaped, got %v", reaped)
+	}
+
+	if len(store.ListSessions()) != 0 {
+		t.Errorf("expected store to be empty")
+	}
+}
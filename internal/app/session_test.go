@@ -0,0 +1,195 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	g "github.com/B33Boy/Judgement/internal/game"
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Session without a run loop, inputs are handled by calling in directly
+func newTestSession(ids ...types.PlayerID) *Session {
+	session := NewSession("session", SessionAccess{})
+	session.cancel()
+	for _, id := range ids {
+		ctx, cancel := context.WithCancel(context.Background())
+		session.AddPlayer(&types.Player{ID: id, PlayerName: string(id), Send: make(chan types.Envelope, 64), Ctx: ctx, Cancel: cancel})
+	}
+	return session
+}
+
+func testInput(session *Session, id types.PlayerID, msgType types.MessageType, payload any) types.GameInput {
+	b, _ := json.Marshal(payload)
+	return types.GameInput{
+		Player: session.players[id],
+		Env:    types.Envelope{Type: msgType, Payload: b},
+	}
+}
+
+func errorCode(err error) types.ErrorCode {
+	var actionErr *types.ActionError
+	if errors.As(err, &actionErr) {
+		return actionErr.Code
+	}
+	return ""
+}
+
+// Most outputs are emitted by the run loop itself, so they must reach the
+// players without waiting on it
+func TestEmitDeliversSynchronously(t *testing.T) {
+	session := NewSession("session", SessionAccess{})
+	defer session.cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	player := &types.Player{ID: "a", Send: make(chan types.Envelope, 64), Ctx: ctx, Cancel: cancel}
+	session.AddPlayer(player)
+
+	for range 64 {
+		session.Emit(types.GameOutput{
+			Players: []types.PlayerID{"a"},
+			Env:     types.Envelope{Type: types.MsgChatMessage},
+		})
+	}
+	if len(player.Send) != 64 {
+		t.Errorf("expected every output to be delivered on return, got %d", len(player.Send))
+	}
+}
+
+func TestRematchNeedsReadyPlayersOfLastGame(t *testing.T) {
+	session := newTestSession("a", "b", "c")
+	session.game = g.NewGame(session, session.Rules(), []types.PlayerID{"a", "b"}, "a")
+	session.game.Abandon()
+
+	// c only watched the last game, so only a and b have to confirm
+	if err := session.handleRematch(testInput(session, "a", types.MsgRematch, Rematch{})); errorCode(err) != types.ErrNotAllowed {
+		t.Fatalf("expected rematch to wait for ready players, got %v", err)
+	}
+
+	for _, id := range []types.PlayerID{"a", "b"} {
+		if err := session.handleSetReady(testInput(session, id, types.MsgSetReady, SetReady{Ready: true})); err != nil {
+			t.Fatalf("unexpected error readying up after the game: %v", err)
+		}
+	}
+	if err := session.handleRematch(testInput(session, "a", types.MsgRematch, Rematch{RotateDealer: true})); err != nil {
+		t.Fatalf("unexpected error starting rematch: %v", err)
+	}
+
+	if seats := session.game.Seats(); !slices.Equal(seats, []types.PlayerID{"a", "b"}) {
+		t.Errorf("expected the players of the last game to be seated, got %v", seats)
+	}
+	if _, seated := session.seated["c"]; seated {
+		t.Errorf("expected c to keep watching")
+	}
+	if dealer := session.game.Dealer(); dealer != "b" {
+		t.Errorf("expected the deal to pass to b, got %v", dealer)
+	}
+	if session.IsReady("a") {
+		t.Errorf("expected ready flags to be cleared for the next game")
+	}
+}
+
+func TestAbandonVotesOfPlayersWhoLeft(t *testing.T) {
+	session := newTestSession("a", "b", "c", "d", "e")
+	session.game = g.NewGame(session, session.Rules(), session.Seats(), "a")
+	vote := func(id types.PlayerID) {
+		if err := session.handleAbandonVote(testInput(session, id, types.MsgAbandonGame, AbandonVote{Abandon: true})); err != nil {
+			t.Fatalf("unexpected error voting: %v", err)
+		}
+	}
+
+	// A vote leaves with the player who cast it
+	vote("a")
+	session.RemovePlayer(session.players["a"])
+	if status := session.abandonStatus(); len(status.Votes) != 0 || status.Needed != 3 {
+		t.Fatalf("expected the vote to go and 3 of the 4 left to be needed, got %+v", status)
+	}
+
+	// Two votes of four is not enough, once d leaves it is a majority
+	vote("b")
+	vote("c")
+	session.tick(time.Now())
+	if session.game.IsOver() {
+		t.Fatalf("expected half of the table not to end the game")
+	}
+	session.RemovePlayer(session.players["d"])
+	session.tick(time.Now())
+	if !session.game.IsOver() {
+		t.Errorf("expected the game to be abandoned by the players left")
+	}
+}
+
+func TestClaimSeatKeepsConnectionID(t *testing.T) {
+	session := newTestSession("a", "b")
+	session.game = g.NewGame(session, session.Rules(), session.Seats(), "a")
+	session.seatPlayers()
+
+	session.RemovePlayer(session.players["b"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	late := &types.Player{ID: "x", PlayerName: "late", Send: make(chan types.Envelope, 64), Ctx: ctx, Cancel: cancel}
+	session.AddPlayer(late)
+
+	// The write loop reads the id while the seat is taken
+	done := make(chan types.PlayerID)
+	go func() { done <- late.ID }()
+
+	if err := session.handleClaimSeat(testInput(session, "x", types.MsgClaimSeat, TargetPlayer{PlayerID: "b"})); err != nil {
+		t.Fatalf("unexpected error claiming seat: %v", err)
+	}
+	<-done
+
+	if late.ID != "x" {
+		t.Errorf("expected the connection to keep its id, got %v", late.ID)
+	}
+	if session.players["b"] != late || session.memberID(late) != "b" {
+		t.Errorf("expected the connection to play as b")
+	}
+	if _, ok := session.streams["x"]; ok {
+		t.Errorf("expected the connection's stream to move to the seat")
+	}
+
+	session.RemovePlayer(late)
+	if _, ok := session.players["b"]; ok || len(session.seatOf) != 0 {
+		t.Errorf("expected the seat to be vacated when the connection leaves")
+	}
+}
+
+func TestRetriedInputs(t *testing.T) {
+	session := newTestSession("a", "b")
+	withID := func(input types.GameInput, id string) types.GameInput {
+		input.Env.ID = id
+		return input
+	}
+
+	// A rejected input can be corrected and sent again under the same id
+	session.handleInput(withID(testInput(session, "a", types.MsgSetReady, "not a payload"), "1"))
+	session.handleInput(withID(testInput(session, "a", types.MsgSetReady, SetReady{Ready: true}), "1"))
+	if !session.IsReady("a") {
+		t.Fatalf("expected the corrected input to be applied")
+	}
+
+	// Accepted inputs are only applied once
+	session.handleInput(withID(testInput(session, "a", types.MsgSetReady, SetReady{Ready: false}), "1"))
+	if !session.IsReady("a") {
+		t.Errorf("expected the retry to get the cached ack")
+	}
+}
+
+func TestWhisperToSelf(t *testing.T) {
+	session := newTestSession("a", "b")
+	self := types.PlayerID("a")
+	err := session.handleChat(testInput(session, "a", types.MsgChatSend, ChatSend{Text: "hi", To: &self}))
+	if errorCode(err) != types.ErrNotAllowed {
+		t.Errorf("expected whispering to yourself to be refused, got %v", err)
+	}
+
+	other := types.PlayerID("b")
+	if err := session.handleChat(testInput(session, "a", types.MsgChatSend, ChatSend{Text: "hi", To: &other})); err != nil {
+		t.Errorf("unexpected error whispering to b: %v", err)
+	}
+}
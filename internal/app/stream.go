@@ -0,0 +1,119 @@
+package app
+
+// Per-player outbound sequence numbers and replay of missed messages
+
+import (
+	"encoding/json"
+	"log"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const replayBufferSize = 256 // per player
+
+type Resume struct {
+	LastSeq uint64 `json:"lastSeq"`
+}
+
+// Recent outbound messages of one player, oldest first
+type outStream struct {
+	lastSeq uint64
+	buffer  []t.Envelope
+}
+
+func (st *outStream) stamp(env t.Envelope) t.Envelope {
+	st.lastSeq++
+	env.Seq = st.lastSeq
+
+	st.buffer = append(st.buffer, env)
+	if len(st.buffer) > replayBufferSize {
+		st.buffer = st.buffer[len(st.buffer)-replayBufferSize:]
+	}
+	return env
+}
+
+// Messages after lastSeq, false if some of them are no longer buffered
+func (st *outStream) since(lastSeq uint64) ([]t.Envelope, bool) {
+	if lastSeq >= st.lastSeq {
+		return nil, true
+	}
+	if len(st.buffer) == 0 || st.buffer[0].Seq > lastSeq+1 {
+		return nil, false
+	}
+
+	start := len(st.buffer) - int(st.lastSeq-lastSeq)
+	return st.buffer[start:], true
+}
+
+// Called with s.mu held
+func (s *Session) deliverLocked(id t.PlayerID, env t.Envelope) {
+	player, connected := s.players[id]
+	stream, ok := s.streams[id]
+	if !ok {
+		if !connected {
+			return // player left before the output was routed
+		}
+		stream = &outStream{}
+		s.streams[id] = stream
+	}
+
+	env = stream.stamp(env)
+	if !connected {
+		return // kept for when the seat is taken back
+	}
+
+	select {
+	case player.Send <- env:
+		// success
+	default:
+		// slow client, they can catch up with resume
+		log.Println("Dropping message for slow player with ID:", id)
+	}
+
+	if player.Legacy && legacyStateEvents[env.Type] {
+		s.resync[id] = true
+	}
+}
+
+func (s *Session) handleResume(input t.GameInput) error {
+	var payload Resume
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid resume request")
+	}
+
+	s.mu.Lock()
+	missed, ok := []t.Envelope(nil), false
+	if stream, exists := s.streams[input.Player.ID]; exists {
+		missed, ok = stream.since(payload.LastSeq)
+	}
+
+	if ok {
+		player := input.Player
+		for _, env := range missed {
+			select {
+			case player.Send <- env:
+			default:
+				log.Println("Replay dropped for slow player with ID:", player.ID)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	// Gap is too old to replay, send a full snapshot instead
+	if !ok {
+		log.Printf("Resyncing player (%v) from seq %d", input.Player.PlayerName, payload.LastSeq)
+		s.syncPlayer(input.Player)
+	}
+	return nil
+}
+
+func (s *Session) syncPlayer(player *t.Player) {
+	sendWelcome(player, s)
+	sendRules(player, s)
+	sendChatHistory(player, s)
+	broadcastPlayersUpdate(s)
+
+	if s.game != nil {
+		s.game.SyncPlayer(player.ID)
+	}
+}
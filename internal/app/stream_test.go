@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestOutStream(t *testing.T) {
+	stream := &outStream{}
+
+	for i := 0; i < replayBufferSize+10; i++ {
+		env := stream.stamp(types.Envelope{Type: types.MsgStateSync})
+		if env.Seq != uint64(i+1) {
+			t.Fatalf("expected seq %d, got %d", i+1, env.Seq)
+		}
+	}
+
+	// Recent gaps can be replayed
+	missed, ok := stream.since(stream.lastSeq - 3)
+	if !ok || len(missed) != 3 || missed[0].Seq != stream.lastSeq-2 {
+		t.Errorf("expected last 3 messages to be replayed, got %d (ok=%v)", len(missed), ok)
+	}
+
+	// Up to date clients get nothing
+	if missed, ok := stream.since(stream.lastSeq); !ok || len(missed) != 0 {
+		t.Errorf("expected nothing to replay")
+	}
+
+	// Messages older than the buffer need a full resync
+	if _, ok := stream.since(5); ok {
+		t.Errorf("expected gap past the buffer to need a resync")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	t "github.com/B33Boy/Judgement/internal/types"
 
 	"github.com/coder/websocket"
-	"github.com/coder/websocket/wsjson"
 )
 
 func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
@@ -21,21 +20,38 @@ func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	session, exists := a.sessionStore.GetSession(sessionId)
+	if !exists {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if err := a.authorizeJoin(session, query.Get("passcode"), query.Get("invite")); err != nil {
+		log.Printf("Join to session (%v) rejected: %v", session.ID, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		OriginPatterns: []string{"localhost:*"},
+		Subprotocols:   subprotocolNames(),
 	})
 	if err != nil {
 		log.Println("websocket accept failed:", err)
 		return
 	}
 
-	session, exists := a.sessionStore.GetSession(sessionId)
-	if !exists {
-		log.Println("session does not exist")
+	proto, err := negotiateProtocol(r, conn.Subprotocol())
+	if err != nil {
+		log.Printf("Join to session (%v) rejected: %v", session.ID, err)
+		conn.Close(websocket.StatusPolicyViolation, err.Error())
 		return
 	}
+	codec := proto.codec()
 
 	player := NewPlayer(playerName, conn)
+	player.Legacy = proto.Version < ProtocolV2
 
 	defer func() {
 		player.Cancel() // stops write loop
@@ -57,7 +73,7 @@ func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
 				if !ok {
 					return
 				}
-				if err := wsjson.Write(r.Context(), conn, env); err != nil {
+				if err := codec.Write(r.Context(), conn, proto.outbound(env)); err != nil {
 					log.Println("write to websocket failed:", err)
 					return
 				}
@@ -69,8 +85,8 @@ func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
 
 	// ====== Read Loop ======
 	for {
-		var env t.Envelope
-		if err := wsjson.Read(r.Context(), conn, &env); err != nil {
+		env, err := codec.Read(r.Context(), conn)
+		if err != nil {
 
 			status := websocket.CloseStatus(err)
 
@@ -84,7 +100,7 @@ func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := handleIncomingMessage(session, player, env); err != nil {
+		if err := handleIncomingMessage(session, player, proto.inbound(env)); err != nil {
 			return
 		}
 	}
@@ -94,6 +110,8 @@ func (a *App) wsHandler(w http.ResponseWriter, r *http.Request) {
 func onPlayerJoin(session *Session, player *t.Player) {
 	session.AddPlayer(player)
 	sendWelcome(player, session)
+	sendChatHistory(player, session)
+	sendRules(player, session)
 	broadcastPlayersUpdate(session)
 	log.Printf("Player (%v) added to session (%v)\n", player.PlayerName, session.ID)
 }
@@ -105,33 +123,49 @@ func onPlayerLeave(session *Session, player *t.Player) {
 }
 
 func sendWelcome(player *t.Player, session *Session) {
+	id := session.memberID(player)
 	out := t.GameOutput{
-		Players: []t.PlayerID{player.ID},
+		Players: []t.PlayerID{id},
 		Env: t.Envelope{
 			Type:    t.MsgWelcome,
-			Payload: mustMarshal(player.ID),
+			Payload: mustMarshal(Welcome{PlayerID: id, ProtocolVersion: CurrentProtocol}),
 		},
 	}
 
-	select {
-	case session.outputs <- out:
-	case <-session.ctx.Done():
-		log.Printf("[sendWelcome] Closed session %v", session.ID)
+	session.Emit(out)
+}
+
+func sendChatHistory(player *t.Player, session *Session) {
+	id := session.memberID(player)
+	out := t.GameOutput{
+		Players: []t.PlayerID{id},
+		Env: t.Envelope{
+			Type:    t.MsgChatHistory,
+			Payload: mustMarshal(session.ChatHistoryFor(id)),
+		},
 	}
+
+	session.Emit(out)
 }
 
-func broadcastPlayersUpdate(session *Session) {
-	players := session.CopyPlayerList()
+func sendRules(player *t.Player, session *Session) {
+	out := t.GameOutput{
+		Players: []t.PlayerID{session.memberID(player)},
+		Env: t.Envelope{
+			Type:    t.MsgRulesUpdate,
+			Payload: mustMarshal(session.Rules()),
+		},
+	}
+
+	session.Emit(out)
+}
 
-	public := make([]PlayerPublic, 0, len(players))
-	allIDs := make([]t.PlayerID, 0, len(players))
+func broadcastPlayersUpdate(session *Session) {
+	public := session.PublicPlayers()
 
-	for _, p := range players {
+	allIDs := make([]t.PlayerID, 0, len(public))
+	for _, p := range public {
 		allIDs = append(allIDs, p.ID)
-		public = append(public, PlayerPublic{
-			ID:   p.ID,
-			Name: p.PlayerName,
-		})
 	}
 
 	out := t.GameOutput{
@@ -142,11 +176,7 @@ func broadcastPlayersUpdate(session *Session) {
 		},
 	}
 
-	select {
-	case session.outputs <- out:
-	case <-session.ctx.Done():
-		log.Printf("[broadcastPlayersUpdate] Closed session %v", session.ID)
-	}
+	session.Emit(out)
 }
 
 func handleIncomingMessage(session *Session, player *t.Player, env t.Envelope) error {
@@ -0,0 +1,124 @@
+package game
+
+// A simple card playing bot, used to play out simulated deals and for the
+// seats bots take at the table
+
+import (
+	"slices"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const botBidSamples = 200 // deals simulated when a bot at the table bids
+
+// Orders cards by how likely they are to take a trick, trumps above everything else
+func cardStrength(card Card, trump *Suit) int {
+	strength := int(card.Rank)
+	if trump != nil && card.Suit == *trump {
+		strength += int(Ace)
+	}
+	return strength
+}
+
+// Card the bot plays from hand. When it wants the trick it plays the cheapest
+// card that takes it, otherwise the strongest card that still loses. Leads
+// are its strongest card when it wants tricks and its weakest otherwise.
+func botCard(hand Hand, trick []Play, trump *Suit, want bool) Card {
+	stack := make([]Card, len(trick))
+	for i, play := range trick {
+		stack[i] = play.Card
+	}
+
+	var legal []Card
+	for _, card := range hand {
+		if canPlay(hand, card, stack, trump) {
+			legal = append(legal, card)
+		}
+	}
+
+	weakest, strongest := legal[0], legal[0]
+	for _, card := range legal[1:] {
+		if cardStrength(card, trump) < cardStrength(weakest, trump) {
+			weakest = card
+		}
+		if cardStrength(card, trump) > cardStrength(strongest, trump) {
+			strongest = card
+		}
+	}
+	if len(trick) == 0 {
+		if want {
+			return strongest
+		}
+		return weakest
+	}
+
+	best := trick[0].Card
+	for _, play := range trick[1:] {
+		if beats(play.Card, best, trick[0].Card.Suit, trump) {
+			best = play.Card
+		}
+	}
+
+	var pick *Card
+	for _, card := range legal {
+		wins := beats(card, best, trick[0].Card.Suit, trump)
+		if wins != want {
+			continue
+		}
+		// Cheapest winner or strongest loser
+		if pick == nil || (want && cardStrength(card, trump) < cardStrength(*pick, trump)) ||
+			(!want && cardStrength(card, trump) > cardStrength(*pick, trump)) {
+			pick = &card
+		}
+	}
+	if pick != nil {
+		return *pick
+	}
+	return weakest
+}
+
+// Plays every hand out with botCard, finishing the trick led by leader first,
+// and returns the tricks each player took. want reports whether a player is
+// after the next trick. hands is played out in place.
+func botPlayout(seats []t.PlayerID, hands map[t.PlayerID]Hand, leader t.PlayerID, trick []Play,
+	trump *Suit, want func(id t.PlayerID, won map[t.PlayerID]int) bool) map[t.PlayerID]int {
+
+	won := make(map[t.PlayerID]int, len(seats))
+	start := slices.Index(seats, leader)
+	trick = slices.Clone(trick)
+
+	for len(trick) > 0 || len(hands[seats[start]]) > 0 {
+		for i := len(trick); i < len(seats); i++ {
+			id := seats[(start+i)%len(seats)]
+			card := botCard(hands[id], trick, trump, want(id, won))
+			if trump == nil {
+				suit := card.Suit // first card of a no trump round picks trump
+				trump = &suit
+			}
+			hands[id] = removeCard(hands[id], card)
+			trick = append(trick, Play{Player: id, Card: card})
+		}
+
+		winner := trick[0]
+		for _, play := range trick[1:] {
+			if beats(play.Card, winner.Card, trick[0].Card.Suit, trump) {
+				winner = play
+			}
+		}
+		won[winner.Player]++
+		start = slices.Index(seats, winner.Player)
+		trick = trick[:0]
+	}
+	return won
+}
+
+// Copy of hand without card
+func removeCard(hand Hand, card Card) Hand {
+	out := make(Hand, 0, len(hand))
+	for _, c := range hand {
+		if !c.Equals(card) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
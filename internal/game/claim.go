@@ -0,0 +1,351 @@
+package game
+
+// Claiming the rest of a round. The player on turn claims exactly how many of
+// the remaining tricks they will take. Claims the server can prove against
+// every legal continuation are played out straight away, the rest need every
+// other connected player to accept. Bots and players who left never accept
+// on anyone's behalf.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const (
+	claimWindow      = 20 * time.Second
+	claimSearchLimit = 500_000 // positions looked at before a claim is refused
+)
+
+var errClaimTooLarge = errors.New("too many continuations to check the claim")
+
+type Claim struct {
+	Tricks int `json:"tricks"` // of the remaining tricks, including the one in progress
+}
+
+type ClaimResponse struct {
+	Accept bool `json:"accept"`
+}
+
+type ClaimMade struct {
+	Player   t.PlayerID `json:"player"`
+	Tricks   int        `json:"tricks"`
+	Deadline time.Time  `json:"deadline"`
+}
+
+type ClaimResolved struct {
+	Player   t.PlayerID `json:"player"`
+	Tricks   int        `json:"tricks"`
+	Accepted bool       `json:"accepted"`
+	Verified bool       `json:"verified"` // proven by the server, nobody had to vote
+	Reason   string     `json:"reason,omitempty"`
+}
+
+type pendingClaim struct {
+	*tableVote
+	tricks int
+}
+
+func (g *Game) handleClaim(input t.GameInput) error {
+	claimant, err := g.verifyPlayerTurn(input.Player.ID)
+	if err != nil {
+		return err
+	}
+	if g.claim != nil || g.undo != nil {
+		return t.NewActionError(t.ErrNotAllowed, "Wait for the current vote to finish")
+	}
+
+	var payload Claim
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Cannot read claim")
+	}
+	if payload.Tricks < 0 || payload.Tricks > len(claimant.Cards) {
+		return t.NewActionError(t.ErrInvalidPayload,
+			fmt.Sprintf("You can claim between 0 and %d tricks", len(claimant.Cards)))
+	}
+
+	guaranteed, err := g.newPlayout(claimant.ID).reaches(payload.Tricks, true)
+	if err != nil {
+		return t.NewActionError(t.ErrNotAllowed, "Too many cards left to check a claim")
+	}
+	if guaranteed {
+		g.settleClaim(claimant.ID, payload.Tricks, true)
+		return nil
+	}
+
+	// Not worth a vote if no way of playing the hands gets there
+	possible, err := g.newPlayout(claimant.ID).reaches(payload.Tricks, false)
+	if err != nil {
+		return t.NewActionError(t.ErrNotAllowed, "Too many cards left to check a claim")
+	}
+	if !possible {
+		return t.NewActionError(t.ErrNotAllowed,
+			fmt.Sprintf("You cannot take exactly %d of the remaining tricks", payload.Tricks))
+	}
+	if !g.anyoneToAnswer(claimant.ID) {
+		return t.NewActionError(t.ErrNotAllowed, "Nobody is left to accept a claim the server cannot prove")
+	}
+
+	g.claim = &pendingClaim{tableVote: newTableVote(claimant.ID, claimWindow), tricks: payload.Tricks}
+	g.broadcast(t.MsgClaimMade, ClaimMade{Player: claimant.ID, Tricks: payload.Tricks, Deadline: g.claim.deadline})
+	return nil
+}
+
+func (g *Game) handleClaimResponse(input t.GameInput) error {
+	if g.claim == nil {
+		return t.NewActionError(t.ErrNotFound, "No claim to answer")
+	}
+	voter, seated := g.Players[input.Player.ID]
+	if !seated {
+		return t.NewActionError(t.ErrNotSeated, "Only seated players can answer a claim")
+	}
+	if voter.ID == g.claim.player {
+		return t.NewActionError(t.ErrNotAllowed, "You cannot vote on your own claim")
+	}
+
+	var payload ClaimResponse
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid claim response")
+	}
+
+	if !payload.Accept {
+		g.rejectClaim(voter.PlayerName + " rejected the claim")
+		return nil
+	}
+
+	g.claim.approvals[voter.ID] = true
+	if g.claim.accepted(g) {
+		g.acceptClaim()
+	}
+	return nil
+}
+
+// Passed, with at least one player having actually said yes
+func (c *pendingClaim) accepted(g *Game) bool {
+	return len(c.approvals) > 0 && c.passed(g)
+}
+
+// Whether a connected player other than the claimant can answer a claim
+func (g *Game) anyoneToAnswer(claimant t.PlayerID) bool {
+	for id := range g.Players {
+		if id != claimant && g.connected(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) acceptClaim() {
+	claim := g.claim
+	g.claim = nil
+	g.settleClaim(claim.player, claim.tricks, false)
+}
+
+func (g *Game) rejectClaim(reason string) {
+	claim := g.claim
+	g.claim = nil
+
+	g.broadcast(t.MsgClaimResolved, ClaimResolved{
+		Player: claim.player,
+		Tricks: claim.tricks,
+		Reason: reason,
+	})
+	g.resetTurnTimer(time.Now())
+	g.sendTurnChanged()
+}
+
+// Plays the rest of the round along a line where the claimant takes exactly
+// the claimed tricks, so history and scoring work as for any other round
+func (g *Game) settleClaim(claimant t.PlayerID, tricks int, verified bool) {
+	line, err := g.newPlayout(claimant).line(tricks, verified)
+	if err != nil {
+		// The same search already succeeded, so this should never happen
+		log.Printf("failed to play out claim: %v", err)
+		g.broadcast(t.MsgClaimResolved, ClaimResolved{
+			Player: claimant,
+			Tricks: tricks,
+			Reason: "The claim could not be played out",
+		})
+		return
+	}
+
+	g.broadcast(t.MsgClaimResolved, ClaimResolved{
+		Player:   claimant,
+		Tricks:   tricks,
+		Accepted: true,
+		Verified: verified,
+	})
+
+	g.lastMove = nil
+	g.claiming = true
+	for _, play := range line {
+		g.applyPlay(g.Players[play.Player], play.Card)
+	}
+	g.claiming = false
+}
+
+// The claimant moving on withdraws their claim
+func (g *Game) cancelClaim() {
+	if g.claim != nil {
+		g.rejectClaim("The claim was withdrawn")
+	}
+}
+
+func (g *Game) expireClaim(now time.Time) {
+	switch {
+	case g.claim == nil:
+	case g.claim.accepted(g): // the last holdout left
+		g.acceptClaim()
+	case !g.anyoneToAnswer(g.claim.player):
+		g.rejectClaim("Nobody is left to accept the claim")
+	case !now.Before(g.claim.deadline):
+		g.rejectClaim("Not everyone accepted in time")
+	}
+}
+
+// ================= Search =================
+
+// Rest of the round played on copies of the hands
+type playout struct {
+	seats    []t.PlayerID
+	hands    map[t.PlayerID]Hand
+	trump    *Suit
+	trick    []Play // cards played to the current trick
+	turn     t.PlayerID
+	claimant t.PlayerID
+	won      int // tricks the claimant took since the claim
+	nodes    int
+}
+
+func (g *Game) newPlayout(claimant t.PlayerID) *playout {
+	p := &playout{
+		seats:    g.cycler.Seats(),
+		hands:    make(map[t.PlayerID]Hand, len(g.Players)),
+		trump:    g.state.TrumpSuit,
+		trick:    slices.Clone(g.plays),
+		turn:     g.state.TurnPlayer,
+		claimant: claimant,
+	}
+	for id, player := range g.Players {
+		p.hands[id] = slices.Clone(player.Cards)
+	}
+	return p
+}
+
+// Whether the claimant can end with exactly target tricks. When forced the
+// other players try to stop it, otherwise everyone plays along.
+func (p *playout) reaches(target int, forced bool) (bool, error) {
+	left := len(p.hands[p.turn])
+	if p.won > target || p.won+left < target {
+		return false, nil
+	}
+	if left == 0 {
+		return true, nil
+	}
+
+	p.nodes++
+	if p.nodes > claimSearchLimit {
+		return false, errClaimTooLarge
+	}
+
+	helping := !forced || p.turn == p.claimant
+	for _, card := range p.legal() {
+		undo := p.play(card)
+		ok, err := p.reaches(target, forced)
+		undo()
+		if err != nil {
+			return false, err
+		}
+		if ok == helping {
+			return ok, nil
+		}
+	}
+	return !helping, nil
+}
+
+// Every remaining card in play order, each one keeping the target in reach
+func (p *playout) line(target int, forced bool) ([]Play, error) {
+	var plays []Play
+	for len(p.hands[p.turn]) > 0 {
+		player := p.turn
+		found := false
+		for _, card := range p.legal() {
+			undo := p.play(card)
+			p.nodes = 0
+			ok, err := p.reaches(target, forced)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				plays = append(plays, Play{Player: player, Card: card})
+				found = true
+				break
+			}
+			undo()
+		}
+		if !found {
+			return nil, fmt.Errorf("no line takes exactly %d tricks", target)
+		}
+	}
+	return plays, nil
+}
+
+func (p *playout) legal() []Card {
+	hand := p.hands[p.turn]
+	stack := make([]Card, len(p.trick))
+	for i, play := range p.trick {
+		stack[i] = play.Card
+	}
+
+	var cards []Card
+	for _, card := range hand {
+		if canPlay(hand, card, stack, p.trump) {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// Plays a card for the player on turn, the returned func takes it back
+func (p *playout) play(card Card) func() {
+	player, hand, trick, trump, won := p.turn, p.hands[p.turn], p.trick, p.trump, p.won
+
+	i := slices.Index(hand, card)
+	p.hands[player] = slices.Delete(slices.Clone(hand), i, i+1)
+	if p.trump == nil {
+		suit := card.Suit // first card of a no trump round picks trump
+		p.trump = &suit
+	}
+	p.trick = append(slices.Clip(p.trick), Play{Player: player, Card: card})
+
+	if len(p.trick) == len(p.seats) {
+		winner := p.trickWinner()
+		if winner == p.claimant {
+			p.won++
+		}
+		p.trick = nil
+		p.turn = winner
+	} else {
+		p.turn = p.seats[(slices.Index(p.seats, player)+1)%len(p.seats)]
+	}
+
+	return func() {
+		p.hands[player], p.trick, p.trump, p.won, p.turn = hand, trick, trump, won, player
+	}
+}
+
+func (p *playout) trickWinner() t.PlayerID {
+	lead := p.trick[0].Card.Suit
+	best := p.trick[0]
+	for _, play := range p.trick[1:] {
+		if beats(play.Card, best.Card, lead, p.trump) {
+			best = play
+		}
+	}
+	return best.Player
+}
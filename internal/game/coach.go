@@ -0,0 +1,108 @@
+package game
+
+// Coach mode: the bidder is told how many tricks their hand is likely to
+// take, estimated by dealing the unseen cards at random many times over
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const adviceSamples = 500
+
+type BidAdvice struct {
+	ExpectedTricks float64   `json:"expectedTricks"`
+	Chances        []float64 `json:"chances"` // chance of taking exactly that many tricks
+	SuggestedBid   Bid       `json:"suggestedBid"`
+	Samples        int       `json:"samples"`
+}
+
+// Everyone in the simulation is after every trick, the estimate is what
+// the hand takes against players doing the same
+func estimateTricks(hand Hand, seats []t.PlayerID, me, leader t.PlayerID, trump *Suit,
+	samples int, rng *rand.Rand) BidAdvice {
+
+	var unseen []Card
+	for _, card := range newDeck() {
+		if !hand.Contains(card) {
+			unseen = append(unseen, card)
+		}
+	}
+
+	counts := make([]int, len(hand)+1)
+	total := 0
+	wantAll := func(t.PlayerID, map[t.PlayerID]int) bool { return true }
+
+	for range samples {
+		rng.Shuffle(len(unseen), func(i, j int) { unseen[i], unseen[j] = unseen[j], unseen[i] })
+
+		hands := make(map[t.PlayerID]Hand, len(seats))
+		dealt := 0
+		for _, id := range seats {
+			if id == me {
+				hands[id] = slices.Clone(hand)
+				continue
+			}
+			hands[id] = slices.Clone(unseen[dealt : dealt+len(hand)])
+			dealt += len(hand)
+		}
+
+		won := botPlayout(seats, hands, leader, nil, trump, wantAll)[me]
+		counts[won]++
+		total += won
+	}
+
+	advice := BidAdvice{
+		ExpectedTricks: float64(total) / float64(samples),
+		Chances:        make([]float64, len(counts)),
+		Samples:        samples,
+	}
+	for tricks, n := range counts {
+		advice.Chances[tricks] = float64(n) / float64(samples)
+		if n > counts[advice.SuggestedBid] {
+			advice.SuggestedBid = Bid(tricks)
+		}
+	}
+	return advice
+}
+
+// Advice for the player on turn while bidding, worked out once per round
+func (g *Game) bidAdvice(id t.PlayerID) *BidAdvice {
+	if !g.params.coach || g.sm.state != StateBid || id != g.state.TurnPlayer {
+		return nil
+	}
+	if advice, ok := g.advice[id]; ok {
+		return advice
+	}
+
+	leader, err := g.cycler.After(g.state.Dealer)
+	if err != nil {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	advice := estimateTricks(g.Players[id].Cards, g.cycler.Seats(), id, leader, g.state.TrumpSuit,
+		adviceSamples, rng)
+	g.advice[id] = &advice
+	return &advice
+}
+
+// Sent privately to the bidder, nothing goes out without coach mode
+func (g *Game) sendBidAdvice() {
+	advice := g.bidAdvice(g.state.TurnPlayer)
+	if advice == nil {
+		return
+	}
+
+	payload, _ := json.Marshal(advice)
+	g.emit(t.GameOutput{
+		Players: []t.PlayerID{g.state.TurnPlayer},
+		Env: t.Envelope{
+			Type:    t.MsgBidAdvice,
+			Payload: payload,
+		},
+	})
+}
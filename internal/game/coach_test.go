@@ -0,0 +1,77 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestEstimateTricks(t *testing.T) {
+	hand := func(cards ...string) Hand {
+		out := make(Hand, len(cards))
+		for i, c := range cards {
+			out[i], _ = ParseCard(c)
+		}
+		return out
+	}
+	seats := []types.PlayerID{"a", "b", "c"}
+	rng := rand.New(rand.NewSource(1))
+
+	// Leading the top spades makes spades trump and takes everything
+	strong := estimateTricks(hand("AS", "KS", "QS", "JS"), seats, "a", "a", nil, 200, rng)
+	if strong.ExpectedTricks != 4 || strong.SuggestedBid != 4 || strong.Chances[4] != 1 {
+		t.Errorf("expected all four tricks, got %+v", strong)
+	}
+
+	weak := estimateTricks(hand("2H", "2C", "2D", "3H"), seats, "a", "b", nil, 200, rng)
+	if weak.ExpectedTricks > 1 || len(weak.Chances) != 5 {
+		t.Errorf("expected few tricks from twos and threes, got %+v", weak)
+	}
+}
+
+func TestCoachAdvice(t *testing.T) {
+	adviceTo := func(fs *fakeSession) []types.PlayerID {
+		var to []types.PlayerID
+		for _, out := range fs.outputs {
+			if out.Env.Type == types.MsgBidAdvice {
+				to = append(to, out.Players...)
+			}
+		}
+		fs.outputs = nil
+		return to
+	}
+
+	fs := newFakeSession("a", "b", "c")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+	if to := adviceTo(fs); len(to) != 0 || g.ViewFor("b").BidAdvice != nil {
+		t.Errorf("expected no advice without coach mode")
+	}
+
+	rules := DefaultRules()
+	rules.Coach = true
+	fs = newFakeSession("a", "b", "c")
+	g = NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+	if to := adviceTo(fs); len(to) != 1 || to[0] != "b" {
+		t.Fatalf("expected advice for the first bidder only, got %v", to)
+	}
+	if g.ViewFor("b").BidAdvice == nil || g.ViewFor("c").BidAdvice != nil {
+		t.Errorf("expected advice in the bidder's view only")
+	}
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	if to := adviceTo(fs); len(to) != 1 || to[0] != "c" {
+		t.Errorf("expected advice to follow the bid around, got %v", to)
+	}
+
+	// Paused and resumed bidders get the same estimate again
+	before := *g.advice["c"]
+	g.Pause(time.Now())
+	g.Resume(time.Now())
+	if after := g.advice["c"]; after.ExpectedTricks != before.ExpectedTricks {
+		t.Errorf("expected the estimate to be kept for the round")
+	}
+}
@@ -0,0 +1,151 @@
+package game
+
+// Turn timer, pausing and ending a game early
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+func (g *Game) resetTurnTimer(now time.Time) {
+	if g.params.turnTimeout == 0 || g.IsOver() {
+		g.state.Deadline = nil
+		return
+	}
+	deadline := now.Add(g.params.turnTimeout)
+	g.state.Deadline = &deadline
+}
+
+// Plays for bots on turn and for the turn player once their time runs out,
+// and picks up rounds solved in the background
+func (g *Game) Tick(now time.Time) {
+	g.collectSolutions()
+	g.expireUndo(now)
+	g.expireClaim(now)
+
+	// Nobody is timed out while the table votes on a claim
+	if g.state.Paused || g.IsOver() || g.claim != nil {
+		return
+	}
+	player, ok := g.Players[g.state.TurnPlayer]
+	if !ok {
+		return
+	}
+
+	switch {
+	case player.Bot:
+		if g.undo != nil {
+			return // leave the table time to vote before moving on
+		}
+	case g.state.Deadline == nil || now.Before(*g.state.Deadline):
+		return
+	default:
+		log.Printf("Turn timer expired for %v", player.PlayerName)
+	}
+
+	// Moves are made as the player so they go through the usual checks
+	input := t.GameInput{
+		Player: &t.Player{ID: player.ID, PlayerName: player.PlayerName},
+	}
+
+	var err error
+	switch g.sm.state {
+	case StateBid:
+		payload, _ := json.Marshal(MakeBid{Bid: g.autoBid(player)})
+		input.Env = t.Envelope{Type: t.MsgMakeBid, Payload: payload}
+		err = g.handleBid(input)
+
+	case StatePlay:
+		payload, _ := json.Marshal(g.autoCard(player))
+		input.Env = t.Envelope{Type: t.MsgPlayCard, Payload: payload}
+		err = g.handlePlay(input)
+	}
+	if err != nil {
+		log.Printf("Move made for %v rejected: %v", player.PlayerName, err)
+	}
+}
+
+// Bots bid what coach mode would suggest, players out of time bid nothing
+func (g *Game) autoBid(player *GamePlayer) Bid {
+	if !player.Bot {
+		return 0
+	}
+	leader, err := g.cycler.After(g.state.Dealer)
+	if err != nil {
+		return 0
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	advice := estimateTricks(player.Cards, g.cycler.Seats(), player.ID, leader, g.state.TrumpSuit,
+		botBidSamples, rng)
+	return advice.SuggestedBid
+}
+
+// Bots go after tricks until they have made their bid, players out of time
+// play their first legal card
+func (g *Game) autoCard(player *GamePlayer) Card {
+	if !player.Bot {
+		return g.firstPlayableCard(player)
+	}
+	bid := 0
+	if player.Bid != nil {
+		bid = int(*player.Bid)
+	}
+	return botCard(player.Cards, g.plays, g.state.TrumpSuit, g.state.HandsWon[player.ID] < bid)
+}
+
+func (g *Game) firstPlayableCard(player *GamePlayer) Card {
+	for _, card := range player.Cards {
+		if g.isCardPlayable(player, card) {
+			return card
+		}
+	}
+	return player.Cards[0]
+}
+
+func (g *Game) Pause(now time.Time) {
+	if g.state.Paused || g.IsOver() {
+		return
+	}
+
+	g.state.Paused = true
+	if g.state.Deadline != nil {
+		g.remaining = g.state.Deadline.Sub(now)
+		g.state.Deadline = nil
+	}
+	g.sendTurnChanged()
+}
+
+func (g *Game) Resume(now time.Time) {
+	if !g.state.Paused {
+		return
+	}
+
+	g.state.Paused = false
+	if g.remaining > 0 {
+		deadline := now.Add(g.remaining)
+		g.state.Deadline = &deadline
+		g.remaining = 0
+	}
+	g.sendTurnChanged()
+}
+
+func (g *Game) IsPaused() bool {
+	return g.state.Paused
+}
+
+// Ends the game before the last round, only scored rounds count
+func (g *Game) Abandon() {
+	if g.IsOver() {
+		return
+	}
+	g.endReason = GameAbandoned
+	g.changeState(GameStopped)
+}
+
+func (g *Game) roundsPlayed() int {
+	return g.scored
+}
@@ -19,8 +19,13 @@ func NewPlayerCycler(m PlayerMap) *PlayerCycler {
 	for playerID := range m {
 		keys = append(keys, playerID)
 	}
+	return NewSeatCycler(keys)
+}
+
+// Cycles through players in the given seating order
+func NewSeatCycler(seats []t.PlayerID) *PlayerCycler {
 	return &PlayerCycler{
-		keys:       keys,
+		keys:       append([]t.PlayerID(nil), seats...),
 		index:      0,
 		startIndex: 0,
 		started:    false,
@@ -60,3 +65,17 @@ func (pc *PlayerCycler) CompletedCycle() bool {
 	}
 	return pc.index == pc.startIndex
 }
+
+// Player seated after the given one, without moving the cycler
+func (pc *PlayerCycler) After(player t.PlayerID) (t.PlayerID, error) {
+	for i, id := range pc.keys {
+		if id == player {
+			return pc.keys[(i+1)%len(pc.keys)], nil
+		}
+	}
+	return "", errors.New("player not found")
+}
+
+func (pc *PlayerCycler) Seats() []t.PlayerID {
+	return append([]t.PlayerID(nil), pc.keys...)
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"fmt"
 	"math/rand"
 )
 
@@ -31,26 +32,48 @@ const (
 	Ace
 )
 
+// Sent as text in every message, see notation.go
 type Card struct {
-	Suit Suit `json:"suit"`
-	Rank Rank `json:"rank"`
+	Suit Suit
+	Rank Rank
+}
+
+func (s Suit) Valid() bool {
+	return s >= Spade && s <= Club
+}
+
+func (r Rank) Valid() bool {
+	return r >= Two && r <= Ace
 }
 
 func (s Suit) String() string {
-	return [...]string{"SPADE", "HEART", "DIAMOND", "CLUB"}[s]
+	if !s.Valid() {
+		return fmt.Sprintf("Suit(%d)", int(s))
+	}
+	return suitNames[s]
 }
 
 func (r Rank) String() string {
-	return [...]string{
-		"", "", "2", "3", "4", "5", "6", "7",
-		"8", "9", "10", "JACK", "QUEEN", "KING", "ACE",
-	}[r]
+	if !r.Valid() {
+		return fmt.Sprintf("Rank(%d)", int(r))
+	}
+	return rankNames[r]
 }
 
 func (c Card) String() string {
 	return c.Suit.String() + "-" + c.Rank.String()
 }
 
+func (c Card) Validate() error {
+	if !c.Suit.Valid() {
+		return fmt.Errorf("unknown suit %d", c.Suit)
+	}
+	if !c.Rank.Valid() {
+		return fmt.Errorf("unknown rank %d", c.Rank)
+	}
+	return nil
+}
+
 func sameSuit(a, b Card) bool {
 	return a.Suit == b.Suit
 }
@@ -76,12 +99,13 @@ func (card Card) Equals(other Card) bool {
 type Deck []Card
 type Hand []Card
 
-func getStrHand(hand Hand) []string {
-	strCards := make([]string, len(hand))
-	for i, card := range hand {
-		strCards[i] = card.String()
+func (hand Hand) Contains(card Card) bool {
+	for _, handCard := range hand {
+		if handCard.Equals(card) {
+			return true
+		}
 	}
-	return strCards
+	return false
 }
 
 func newDeck() Deck {
@@ -105,9 +129,8 @@ func shuffleDeck(cards Deck) {
 	}
 }
 
-func distributeCards(deck Deck, playerCnt int) []Hand {
+func distributeCards(deck Deck, playerCnt int, cardsPerPlayer int) []Hand {
 	playerHands := make([]Hand, playerCnt)
-	cardsPerPlayer := 7
 
 	for i := 0; i < playerCnt; i++ {
 		start := i * cardsPerPlayer
@@ -119,8 +142,8 @@ func distributeCards(deck Deck, playerCnt int) []Hand {
 	return playerHands
 }
 
-func getHands(playerCount int) []Hand {
+func getHands(playerCount int, cardsPerPlayer int) []Hand {
 	deck := newDeck()
 	shuffleDeck(deck)
-	return distributeCards(deck, playerCount)
+	return distributeCards(deck, playerCount, cardsPerPlayer)
 }
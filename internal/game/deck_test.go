@@ -1,6 +1,8 @@
 package game
 
 import (
+	"encoding/json"
+	"slices"
 	"testing"
 )
 
@@ -56,7 +58,7 @@ func TestDistributeCards(t *testing.T) {
 	playerCount := 3
 	expectedCardsPerPlayer := 7
 
-	hands := distributeCards(deck, playerCount)
+	hands := distributeCards(deck, playerCount, expectedCardsPerPlayer)
 
 	if len(hands) != playerCount {
 		t.Errorf("Expected %d hands, got %d", playerCount, len(hands))
@@ -68,3 +70,79 @@ func TestDistributeCards(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCard(t *testing.T) {
+	aceOfSpades := Card{Suit: Spade, Rank: Ace}
+	tenOfHearts := Card{Suit: Heart, Rank: Ten}
+
+	valid := map[string]Card{
+		"SPADE-ACE": aceOfSpades,
+		"spade-ace": aceOfSpades,
+		"AS":        aceOfSpades,
+		"10H":       tenOfHearts,
+		"TH":        tenOfHearts,
+		"HEART-10":  tenOfHearts,
+	}
+	for text, want := range valid {
+		got, err := ParseCard(text)
+		if err != nil || got != want {
+			t.Errorf("expected %q to parse as %s, got %s (%v)", text, want, got, err)
+		}
+	}
+
+	for _, text := range []string{"", "A", "1S", "AX", "SPADE-1", "STAR-ACE", "11H"} {
+		if _, err := ParseCard(text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	b, err := json.Marshal(Hand{{Suit: Club, Rank: Two}, {Suit: Diamond, Rank: Queen}})
+	if err != nil || string(b) != `["CLUB-2","DIAMOND-QUEEN"]` {
+		t.Errorf("expected long form cards, got %s (%v)", b, err)
+	}
+
+	var card Card
+	if err := json.Unmarshal([]byte(`"QD"`), &card); err != nil || card != (Card{Suit: Diamond, Rank: Queen}) {
+		t.Errorf("expected short form to decode, got %s (%v)", card, err)
+	}
+
+	if _, err := json.Marshal(Card{Suit: 9, Rank: 20}); err == nil {
+		t.Errorf("expected invalid card to fail encoding")
+	}
+	if s := (Card{Suit: 9, Rank: 20}).String(); s != "Suit(9)-Rank(20)" {
+		t.Errorf("expected invalid card to print safely, got %s", s)
+	}
+}
+
+func TestSortHand(t *testing.T) {
+	parse := func(cards ...string) Hand {
+		hand := make(Hand, len(cards))
+		for i, c := range cards {
+			hand[i], _ = ParseCard(c)
+		}
+		return hand
+	}
+	hand := parse("2S", "KD", "10C", "AS", "3H", "JC")
+
+	sortHand(hand, DefaultHandOrder(), nil)
+	want := parse("AS", "2S", "3H", "JC", "10C", "KD")
+	if !slices.Equal(hand, want) {
+		t.Errorf("expected %v, got %v", want, hand)
+	}
+
+	// Trump leads and the rest still alternate colours
+	trump := Diamond
+	sortHand(hand, DefaultHandOrder(), &trump)
+	want = parse("KD", "AS", "2S", "3H", "JC", "10C")
+	if !slices.Equal(hand, want) {
+		t.Errorf("expected %v, got %v", want, hand)
+	}
+
+	sortHand(hand, HandOrder{}, &trump)
+	want = parse("2S", "AS", "3H", "KD", "10C", "JC")
+	if !slices.Equal(hand, want) {
+		t.Errorf("expected %v, got %v", want, hand)
+	}
+}
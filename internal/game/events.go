@@ -4,24 +4,21 @@ package game
 
 import (
 	"encoding/json"
+	"slices"
 
 	t "github.com/B33Boy/Judgement/internal/types"
 )
 
 func (g *Game) sendGameStarted() {
 	g.emit(t.GameOutput{
-		Players: g.allPlayerIDs(),
+		Players: g.audienceIDs(),
 		Env:     t.Envelope{Type: t.MsgGameStarted},
 	})
 }
 
 func (g *Game) sendCardsToPlayer(player *GamePlayer) {
 
-	strHand := getStrHand(player.Cards)
-
-	payload, _ := json.Marshal(struct {
-		Cards []string `json:"cards"`
-	}{Cards: strHand})
+	payload, _ := json.Marshal(PlayerHand{Cards: player.Cards})
 
 	out := t.GameOutput{
 		Players: []t.PlayerID{player.ID},
@@ -34,40 +31,114 @@ func (g *Game) sendCardsToPlayer(player *GamePlayer) {
 	g.emit(out)
 }
 
-func (g *Game) broadcastGameState() {
+func (g *Game) broadcast(msgType t.MessageType, v any) {
+	g.sendTo(g.audienceIDs(), msgType, v)
+}
 
-	payload, _ := json.Marshal(g.state)
+func (g *Game) sendTo(playerIDs []t.PlayerID, msgType t.MessageType, v any) {
+	payload, _ := json.Marshal(v)
 
 	g.emit(t.GameOutput{
-		Players: g.allPlayerIDs(),
+		Players: playerIDs,
 		Env: t.Envelope{
-			Type:    t.MsgStateSync,
+			Type:    msgType,
 			Payload: payload,
 		},
 	})
 }
 
-func (g *Game) sendGameFinished() {
-	g.emit(t.GameOutput{
-		Players: g.allPlayerIDs(),
-		Env: t.Envelope{
-			Type: t.MsgGameEnd,
-		},
+func (g *Game) sendRoundStarted() {
+	g.broadcast(t.MsgRoundStarted, RoundStarted{
+		Round:          g.state.Round,
+		Dealer:         g.state.Dealer,
+		CardsPerPlayer: g.params.cardsPerRound,
 	})
 }
 
-func (g *Game) sendInvalidMove(playerID t.PlayerID, message string) {
-	payload, _ := json.Marshal(InvalidActionPayload{
-		Message: message,
+func (g *Game) sendBidPlaced(player *GamePlayer, bid Bid) {
+	g.broadcast(t.MsgBidPlaced, BidPlaced{Player: player.ID, Bid: bid})
+}
+
+func (g *Game) sendCardPlayed(player *GamePlayer, card Card) {
+	g.broadcast(t.MsgCardPlayed, CardPlayed{
+		Player:    player.ID,
+		Card:      card,
+		TrumpSuit: g.state.TrumpSuit,
 	})
+}
 
-	out := t.GameOutput{
-		Players: []t.PlayerID{playerID},
+func (g *Game) sendTrickWon(winner t.PlayerID) {
+	g.broadcast(t.MsgTrickWon, TrickWon{Winner: winner, HandsWon: g.state.HandsWon[winner]})
+}
+
+func (g *Game) sendRoundScored() {
+	round := g.state.Round
+	payload := RoundScored{
+		Round:    round,
+		Bids:     make(map[t.PlayerID]Bid, len(g.Players)),
+		HandsWon: make(map[t.PlayerID]int, len(g.Players)),
+		Scores:   make(map[t.PlayerID]Score, len(g.Players)),
+		Totals:   g.Totals(),
+	}
+	for id := range g.Players {
+		payload.Bids[id] = g.state.Bids[id]
+		payload.HandsWon[id] = g.state.HandsWon[id]
+		payload.Scores[id] = g.scores[id][round]
+	}
+	g.broadcast(t.MsgRoundScored, payload)
+}
+
+// Nothing to announce once the game is over, game_end covers it. The player
+// on turn also gets their legal moves.
+func (g *Game) sendTurnChanged() {
+	if g.IsOver() {
+		return
+	}
+	turn := TurnChanged{
+		Player:   g.state.TurnPlayer,
+		State:    g.sm.state,
+		Deadline: g.state.Deadline,
+		Paused:   g.state.Paused,
+	}
+	others := slices.DeleteFunc(g.audienceIDs(), func(id t.PlayerID) bool {
+		return id == turn.Player
+	})
+	g.sendTo(others, t.MsgTurnChanged, turn)
+
+	turn.LegalBids, turn.LegalCards = g.legalMoves(turn.Player)
+	g.sendTo([]t.PlayerID{turn.Player}, t.MsgTurnChanged, turn)
+
+	g.sendBidAdvice()
+}
+
+// Every recipient gets their own view of the game
+func (g *Game) sendGameState(playerIDs []t.PlayerID) {
+	for _, id := range playerIDs {
+		payload, _ := json.Marshal(g.ViewFor(id))
+
+		g.emit(t.GameOutput{
+			Players: []t.PlayerID{id},
+			Env: t.Envelope{
+				Type:    t.MsgStateSync,
+				Payload: payload,
+			},
+		})
+	}
+}
+
+func (g *Game) sendGameFinished(reason GameEndReason) {
+	payload, _ := json.Marshal(GameEndPayload{
+		GameID:       g.ID,
+		Reason:       reason,
+		RoundsPlayed: g.roundsPlayed(),
+		Scores:       g.Totals(),
+	})
+
+	g.emit(t.GameOutput{
+		Players: g.audienceIDs(),
 		Env: t.Envelope{
-			Type:    t.MsgInvalidAction,
+			Type:    t.MsgGameEnd,
 			Payload: payload,
 		},
-	}
-
-	g.emit(out)
+	})
 }
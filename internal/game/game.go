@@ -4,11 +4,12 @@ package game
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"math/rand"
 	"time"
 
 	t "github.com/B33Boy/Judgement/internal/types"
+	"github.com/google/uuid"
 )
 
 // ======================== Game ========================
@@ -16,74 +17,89 @@ import (
 type GameParams struct {
 	maxRounds     Round
 	cardsPerRound int
+	turnTimeout   time.Duration
+	handOrder     HandOrder
+	coach         bool
 }
 
 type GameState struct {
 	Round      Round                `json:"round"`
 	State      State                `json:"state"`
+	Dealer     t.PlayerID           `json:"dealer"`
 	TurnPlayer t.PlayerID           `json:"turnPlayer"`
 	TrumpSuit  *Suit                `json:"trumpSuit"`
 	Table      map[t.PlayerID]*Card `json:"table"` // Cards currently played
 	Bids       map[t.PlayerID]Bid   `json:"bids"`
 	HandsWon   map[t.PlayerID]int   `json:"handsWon"`
+	Paused     bool                 `json:"paused"`
+	Deadline   *time.Time           `json:"deadline,omitempty"` // when the turn player runs out of time
 }
 
 type Game struct {
 	// Engine
-	ctx    context.Context
-	cancel context.CancelFunc
-	emit   func(t.GameOutput)
-	cycler *PlayerCycler
-	sm     *StateMachine
+	ctx       context.Context
+	cancel    context.CancelFunc
+	emit      func(t.GameOutput)
+	members   func() map[t.PlayerID]*t.Player // everyone in the session, seated or not
+	connected func(t.PlayerID) bool
+	cycler    *PlayerCycler
+	sm        *StateMachine
 
 	// Data
+	ID        string
 	Players   PlayerMap
 	params    *GameParams
 	state     *GameState
 	scores    PlayerScore // historical scores
+	scored    int         // rounds scored so far
 	cardstack []Card
+	plays     []Play    // current trick in play order
+	tricks    [][]Trick // completed tricks of each round
+	rounds    []RoundRecord
+	solved    chan roundSolution        // double-dummy results from the background
+	advice    map[t.PlayerID]*BidAdvice // coach estimates for this round's bidders
+
+	// Control
+	remaining time.Duration // turn time left when paused
+	endReason GameEndReason
+	lastMove  *snapshot     // state before the last bid or play
+	undo      *tableVote    // pending undo request
+	claim     *pendingClaim // claim waiting for the other players
+	claiming  bool          // plays are being made to settle a claim
 }
 
 type SessionView interface {
 	Context() context.Context
 	GetPlayers() map[t.PlayerID]*t.Player
+	IsConnected(t.PlayerID) bool
 	Emit(t.GameOutput)
 }
 
-func NewGame(session SessionView) *Game {
+// Seats the given players in that order. Dealer is picked at random when it
+// is empty or not seated.
+func NewGame(session SessionView, rules Rules, seats []t.PlayerID, dealer t.PlayerID) *Game {
 	players := session.GetPlayers()
-	playerCnt := len(players)
+	playerCnt := len(seats)
 
-	hands := getHands(playerCnt)
 	gamePlayers := make(PlayerMap)
-
-	i := 0
-	for playerID, player := range players {
+	for _, playerID := range seats {
 		gamePlayers[playerID] = &GamePlayer{
 			ID:         playerID,
-			PlayerName: player.PlayerName,
+			PlayerName: players[playerID].PlayerName,
+			Bot:        players[playerID].Bot,
 			Bid:        nil,
-			Cards:      hands[i],
+			Cards:      nil,
 		}
-		i++
 	}
 
 	ctx, cancel := context.WithCancel(session.Context())
 
 	// Cycler
-	cycler := NewPlayerCycler(gamePlayers)
-
-	keys := make([]t.PlayerID, 0, len(gamePlayers))
-	for id := range gamePlayers {
-		keys = append(keys, id)
-	}
-
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	firstPlayerID := keys[rng.Intn(len(keys))]
+	cycler := NewSeatCycler(seats)
 
-	err := cycler.StartFrom(firstPlayerID)
-	if err != nil {
-		log.Println("failed to start cycler:", err)
+	if _, ok := gamePlayers[dealer]; !ok {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		dealer = seats[rng.Intn(len(seats))]
 	}
 
 	// State Machine
@@ -92,17 +108,23 @@ func NewGame(session SessionView) *Game {
 	sm.AddTransition(StatePlay, PlayingDone, StateResolution)
 	sm.AddTransition(StateResolution, PlayingContinue, StateBid)
 	sm.AddTransition(StateResolution, GameDone, StateGameOver)
+	sm.AddTransition(StateBid, GameStopped, StateGameOver)
+	sm.AddTransition(StatePlay, GameStopped, StateGameOver)
 
 	// Params
 	params := &GameParams{
-		maxRounds:     14,
-		cardsPerRound: 7,
+		maxRounds:     rules.MaxRounds,
+		cardsPerRound: rules.CardsPerRound,
+		turnTimeout:   rules.TurnTimeout(),
+		handOrder:     rules.HandOrder,
+		coach:         rules.Coach,
 	}
 
 	gameState := &GameState{
 		Round:      0,
 		State:      StateBid,
-		TurnPlayer: firstPlayerID,
+		Dealer:     dealer,
+		TurnPlayer: "",
 		TrumpSuit:  nil,
 		Table:      make(map[t.PlayerID]*Card),
 		Bids:       make(map[t.PlayerID]Bid),
@@ -112,19 +134,32 @@ func NewGame(session SessionView) *Game {
 	// Scores
 	scoreboard := NewScoreboard(playerCnt, gamePlayers, params.maxRounds)
 
-	return &Game{
-		ctx:    ctx,
-		cancel: cancel,
-		emit:   session.Emit,
-		cycler: cycler,
-		sm:     sm,
+	g := &Game{
+		ctx:       ctx,
+		cancel:    cancel,
+		emit:      session.Emit,
+		members:   session.GetPlayers,
+		connected: session.IsConnected,
+		cycler:    cycler,
+		sm:        sm,
 
+		ID:        uuid.NewString(),
 		Players:   gamePlayers,
 		params:    params,
 		state:     gameState,
 		scores:    scoreboard,
 		cardstack: make([]Card, 0),
+		tricks:    make([][]Trick, params.maxRounds),
+		rounds:    make([]RoundRecord, params.maxRounds),
+		solved:    make(chan roundSolution, params.maxRounds), // never blocks the solvers
+		advice:    make(map[t.PlayerID]*BidAdvice),
+
+		endReason: GameCompleted,
 	}
+
+	g.dealRound()
+
+	return g
 }
 
 func (g *Game) Start() {
@@ -135,19 +170,102 @@ func (g *Game) Start() {
 		g.sendCardsToPlayer(player)
 	}
 
-	// Send Round # and Send Turn PlayerId
-	g.broadcastGameState()
+	// Everyone gets a snapshot to apply later events to
+	g.resetTurnTimer(time.Now())
+	g.sendGameState(g.audienceIDs())
+	g.sendBidAdvice()
+}
+
+// Applies a bid or card play, rejected inputs return a *t.ActionError
+func (g *Game) HandleGameInput(input t.GameInput) error {
+	// Looking back is fine at any point, even while paused
+	if input.Env.Type == t.MsgGetTrickHistory {
+		return g.handleTrickHistory(input)
+	}
+
+	if g.state.Paused {
+		return t.NewActionError(t.ErrGamePaused, "Game is paused")
+	}
+
+	switch input.Env.Type {
+	case t.MsgMakeBid:
+		if g.sm.state != StateBid {
+			return t.NewActionError(t.ErrWrongPhase, "Bidding is over")
+		}
+		return g.handleBid(input)
+
+	case t.MsgPlayCard:
+		if g.sm.state != StatePlay {
+			return t.NewActionError(t.ErrWrongPhase, "Cards cannot be played yet")
+		}
+		return g.handlePlay(input)
+
+	case t.MsgClaim:
+		if g.sm.state != StatePlay {
+			return t.NewActionError(t.ErrWrongPhase, "Tricks can only be claimed while playing")
+		}
+		return g.handleClaim(input)
+
+	case t.MsgClaimResponse:
+		return g.handleClaimResponse(input)
+
+	case t.MsgUndoRequest:
+		return g.handleUndoRequest(input)
+
+	case t.MsgUndoResponse:
+		return g.handleUndoResponse(input)
+	}
+
+	return t.NewActionError(t.ErrUnknownMessage, fmt.Sprintf("Unknown message type %q", input.Env.Type))
 }
 
-func (g *Game) HandleGameInput(input t.GameInput) {
-	switch g.sm.state {
-	case StateBid:
-		g.handleBid(input)
+func (g *Game) IsOver() bool {
+	return g.sm.state == StateGameOver
+}
+
+func (g *Game) Dealer() t.PlayerID {
+	return g.state.Dealer
+}
+
+// Seating order of the players
+func (g *Game) Seats() []t.PlayerID {
+	return g.cycler.Seats()
+}
 
-	case StatePlay:
-		g.handlePlay(input)
+// Sum of every round scored so far
+func (g *Game) Totals() map[t.PlayerID]Score {
+	totals := make(map[t.PlayerID]Score, len(g.scores))
+	for id, rounds := range g.scores {
+		for _, score := range rounds {
+			totals[id] += score
+		}
+	}
+	return totals
+}
+
+// Brings a single player up to date, e.g. after taking over a seat.
+// Spectators get the table without a hand.
+func (g *Game) SyncPlayer(id t.PlayerID) {
+	g.emit(t.GameOutput{
+		Players: []t.PlayerID{id},
+		Env:     t.Envelope{Type: t.MsgGameStarted},
+	})
+	if player, ok := g.Players[id]; ok {
+		g.sendCardsToPlayer(player)
+	}
+	g.sendGameState([]t.PlayerID{id})
+}
+
+// Sends the current view to players who don't follow the game events
+func (g *Game) SyncState(ids []t.PlayerID) {
+	g.sendGameState(ids)
+}
 
-	case StateResolution:
-		g.handleResolution(input)
+// Hands a seat to a new player, who keeps its hand, bid and scores and
+// makes its moves from now on if a bot had it
+func (g *Game) HandOverSeat(id t.PlayerID, name string) {
+	if player, ok := g.Players[id]; ok {
+		player.PlayerName = name
+		player.Bot = false
 	}
 }
@@ -0,0 +1,727 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Minimal SessionView that records everything the game emits
+type fakeSession struct {
+	ctx     context.Context
+	players map[types.PlayerID]*types.Player
+	seats   []types.PlayerID
+	outputs []types.GameOutput
+}
+
+func newFakeSession(ids ...types.PlayerID) *fakeSession {
+	fs := &fakeSession{
+		ctx:     context.Background(),
+		players: make(map[types.PlayerID]*types.Player),
+		seats:   ids,
+	}
+	for _, id := range ids {
+		fs.players[id] = &types.Player{ID: id, PlayerName: string(id)}
+	}
+	return fs
+}
+
+func (fs *fakeSession) Context() context.Context                     { return fs.ctx }
+func (fs *fakeSession) GetPlayers() map[types.PlayerID]*types.Player { return fs.players }
+func (fs *fakeSession) Emit(out types.GameOutput)                    { fs.outputs = append(fs.outputs, out) }
+
+// Bots have no connection, like in the session
+func (fs *fakeSession) IsConnected(id types.PlayerID) bool {
+	player, ok := fs.players[id]
+	return ok && !player.Bot
+}
+
+func input(fs *fakeSession, id types.PlayerID, msgType types.MessageType, payload any) types.GameInput {
+	b, _ := json.Marshal(payload)
+	return types.GameInput{
+		Player: fs.players[id],
+		Env:    types.Envelope{Type: msgType, Payload: b},
+	}
+}
+
+func legalCard(g *Game, player *GamePlayer) Card {
+	for _, card := range player.Cards {
+		if g.isCardPlayable(player, card) {
+			return card
+		}
+	}
+	return player.Cards[0]
+}
+
+func TestFullGame(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	rules := DefaultRules()
+	rules.MaxRounds = 3
+	rules.CardsPerRound = 4
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+
+	if g.state.TurnPlayer != "b" {
+		t.Fatalf("expected player after dealer to bid first, got %s", g.state.TurnPlayer)
+	}
+
+	for round := Round(0); round < rules.MaxRounds; round++ {
+		if g.state.Round != round {
+			t.Fatalf("expected round %d, got %d", round, g.state.Round)
+		}
+
+		// Everyone bids one
+		for range g.Players {
+			g.HandleGameInput(input(fs, g.state.TurnPlayer, types.MsgMakeBid, MakeBid{Bid: 1}))
+		}
+		if g.sm.state != StatePlay {
+			t.Fatalf("expected playing state after bids, got %s", g.sm.state)
+		}
+
+		for trick := 0; trick < rules.CardsPerRound; trick++ {
+			for range g.Players {
+				player := g.Players[g.state.TurnPlayer]
+				g.HandleGameInput(input(fs, player.ID, types.MsgPlayCard, legalCard(g, player)))
+			}
+		}
+	}
+
+	if !g.IsOver() {
+		t.Fatalf("expected game to be over, state is %s", g.sm.state)
+	}
+
+	// Every trick was won by someone
+	for id, rounds := range g.scores {
+		for i, score := range rounds {
+			if score != 0 && score != 11 {
+				t.Errorf("unexpected score %d for %s in round %d", score, id, i)
+			}
+		}
+	}
+
+	ended := false
+	for _, out := range fs.outputs {
+		ended = ended || out.Env.Type == types.MsgGameEnd
+	}
+	if !ended {
+		t.Errorf("expected game_end to be sent")
+	}
+
+	record := g.Record()
+	if len(record.Rounds) != int(rules.MaxRounds) {
+		t.Fatalf("expected %d rounds in the record, got %d", rules.MaxRounds, len(record.Rounds))
+	}
+	for _, round := range record.Rounds {
+		if len(round.Tricks) != rules.CardsPerRound || len(round.Deal.Hands["a"]) != rules.CardsPerRound {
+			t.Errorf("expected every trick and the full deal of round %d", round.Round)
+		}
+	}
+
+	// Small deals are solved in the background and picked up on a tick
+	fs.outputs = nil
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fs.outputs) < len(record.Rounds) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		g.Tick(time.Now())
+	}
+	for _, out := range fs.outputs {
+		var solved RoundSolved
+		if out.Env.Type != types.MsgRoundSolved || json.Unmarshal(out.Env.Payload, &solved) != nil {
+			t.Fatalf("expected only round_solved, got %s", out.Env.Type)
+		}
+		if g.rounds[solved.Round].DoubleDummy == nil {
+			t.Errorf("expected round %d to keep its solution", solved.Round)
+		}
+	}
+	if len(fs.outputs) != len(record.Rounds) {
+		t.Errorf("expected every round to be solved, got %d of %d", len(fs.outputs), len(record.Rounds))
+	}
+}
+
+func TestTrickWinner(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+
+	heart := Heart
+	g.state.TrumpSuit = &heart
+
+	plays := []struct {
+		id   types.PlayerID
+		card Card
+	}{
+		{"a", Card{Suit: Spade, Rank: Ace}},
+		{"b", Card{Suit: Heart, Rank: Two}},
+		{"c", Card{Suit: Spade, Rank: King}},
+	}
+	for _, p := range plays {
+		g.addCardToTable(g.Players[p.id], p.card)
+	}
+
+	if winner := g.trickWinner(); winner != "b" {
+		t.Errorf("expected trump to win the trick, got %s", winner)
+	}
+
+	g.state.TrumpSuit = nil
+	if winner := g.trickWinner(); winner != "a" {
+		t.Errorf("expected highest card of led suit to win, got %s", winner)
+	}
+}
+
+func TestCanPlayFollowsLead(t *testing.T) {
+	card := func(s string) Card {
+		c, _ := ParseCard(s)
+		return c
+	}
+	heart := Heart
+	hand := Hand{card("KS"), card("2D"), card("9C")}
+
+	// Spades were led, a diamond on top does not change what has to be followed
+	stack := []Card{card("AS"), card("5D")}
+	if canPlay(hand, card("2D"), stack, nil) {
+		t.Errorf("expected a spade to be required when spades were led")
+	}
+	if !canPlay(hand, card("KS"), stack, nil) {
+		t.Errorf("expected following the suit led to be allowed")
+	}
+
+	// Trump is always an option, anything goes without the suit or trump
+	if !canPlay(Hand{card("2H"), card("KS")}, card("2H"), stack, &heart) {
+		t.Errorf("expected trump to be playable")
+	}
+	if !canPlay(Hand{card("2D"), card("9C")}, card("9C"), stack, &heart) {
+		t.Errorf("expected any card when the suit led cannot be followed")
+	}
+}
+
+func TestTurnTimerAndPause(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	rules := DefaultRules()
+	rules.TurnSeconds = 10
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+	now := time.Now()
+
+	// Paused games do not time out and reject moves
+	g.Pause(now)
+	g.Tick(now.Add(time.Minute))
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	if g.state.TurnPlayer != "b" {
+		t.Fatalf("expected no moves while paused, turn is %s", g.state.TurnPlayer)
+	}
+
+	// Remaining time is kept across the pause
+	g.Resume(now.Add(time.Minute))
+	if g.state.Deadline == nil || g.state.Deadline.Before(now.Add(time.Minute)) {
+		t.Fatalf("expected deadline after resume, got %v", g.state.Deadline)
+	}
+
+	g.Tick(g.state.Deadline.Add(time.Second))
+	if g.state.TurnPlayer != "c" {
+		t.Errorf("expected timed out player to be skipped, turn is %s", g.state.TurnPlayer)
+	}
+	if bid := g.Players["b"].Bid; bid == nil || *bid != 0 {
+		t.Errorf("expected timed out player to bid 0, got %v", bid)
+	}
+}
+
+func TestBotsMoveOnTick(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	fs.players["b"].Bot = true
+	rules := DefaultRules()
+	rules.MaxRounds = 2
+	rules.CardsPerRound = 3
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+
+	// a only ever moves when it is their turn, b is left to the ticks
+	for range 100 {
+		if g.IsOver() {
+			break
+		}
+		if g.state.TurnPlayer != "a" {
+			g.Tick(time.Now())
+			continue
+		}
+		a := g.Players["a"]
+		if g.sm.state == StateBid {
+			g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 1}))
+		} else {
+			g.HandleGameInput(input(fs, "a", types.MsgPlayCard, legalCard(g, a)))
+		}
+	}
+	if !g.IsOver() {
+		t.Fatalf("expected the bot to play the game out with a")
+	}
+}
+
+func TestAbandon(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	g.Abandon()
+	if !g.IsOver() {
+		t.Fatalf("expected abandoned game to be over")
+	}
+
+	last := fs.outputs[len(fs.outputs)-1]
+	var payload GameEndPayload
+	if err := json.Unmarshal(last.Env.Payload, &payload); err != nil {
+		t.Fatalf("expected game_end payload: %v", err)
+	}
+	if payload.Reason != GameAbandoned || payload.RoundsPlayed != 0 {
+		t.Errorf("unexpected game_end payload: %+v", payload)
+	}
+}
+
+func TestRejectedMoves(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	expectCode := func(err error, code types.ErrorCode) {
+		t.Helper()
+		actionErr, ok := err.(*types.ActionError)
+		if !ok || actionErr.Code != code {
+			t.Errorf("expected %s, got %v", code, err)
+		}
+	}
+
+	expectCode(g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 1})), types.ErrNotYourTurn)
+	expectCode(g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 8})), types.ErrBidOutOfRange)
+	expectCode(g.HandleGameInput(input(fs, "b", types.MsgPlayCard, g.Players["b"].Cards[0])), types.ErrWrongPhase)
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 1}))
+
+	// Card from the other player's hand
+	expectCode(g.HandleGameInput(input(fs, "b", types.MsgPlayCard, g.Players["a"].Cards[0])), types.ErrCardNotInHand)
+}
+
+func TestViewHidesOtherHands(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	view := g.ViewFor("b")
+	if len(view.Hand) != len(g.Players["b"].Cards) {
+		t.Errorf("expected own hand in view, got %d cards", len(view.Hand))
+	}
+	if len(view.LegalBids) != len(view.Hand)+1 {
+		t.Errorf("expected bids 0..%d to be legal, got %v", len(view.Hand), view.LegalBids)
+	}
+
+	other := g.ViewFor("c")
+	if other.LegalBids != nil {
+		t.Errorf("expected no legal bids when it is not your turn")
+	}
+
+	spectator := g.ViewFor("z")
+	if spectator.Hand != nil {
+		t.Errorf("expected spectators to see no hand")
+	}
+	for _, seat := range spectator.Seats {
+		if seat.CardCount != len(g.Players[seat.ID].Cards) {
+			t.Errorf("expected card count for %s", seat.ID)
+		}
+	}
+}
+
+func TestIncrementalEvents(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	rules := DefaultRules()
+	rules.MaxRounds = 2
+	rules.CardsPerRound = 1
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+
+	drain := func() []types.MessageType {
+		var seen []types.MessageType
+		for _, out := range fs.outputs {
+			if len(seen) == 0 || seen[len(seen)-1] != out.Env.Type {
+				seen = append(seen, out.Env.Type)
+			}
+		}
+		fs.outputs = nil
+		return seen
+	}
+	drain()
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 0}))
+	for _, msgType := range drain() {
+		if msgType == types.MsgStateSync {
+			t.Fatalf("expected no state_sync after a bid")
+		}
+	}
+
+	g.HandleGameInput(input(fs, "b", types.MsgPlayCard, legalCard(g, g.Players["b"])))
+	g.HandleGameInput(input(fs, "a", types.MsgPlayCard, legalCard(g, g.Players["a"])))
+
+	want := []types.MessageType{
+		types.MsgPlayerHand, types.MsgCardPlayed, types.MsgTrickWon, types.MsgRoundScored,
+		types.MsgRoundStarted, types.MsgPlayerHand, types.MsgTurnChanged,
+	}
+	got := drain()
+	got = got[len(got)-len(want):]
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSpectatorsFollowTheTable(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	// x joined the session but has no seat
+	fs.players["x"] = &types.Player{ID: "x", PlayerName: "x"}
+	fs.outputs = nil
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+
+	received := func(id types.PlayerID) []types.MessageType {
+		var got []types.MessageType
+		for _, out := range fs.outputs {
+			if slices.Contains(out.Players, id) {
+				got = append(got, out.Env.Type)
+			}
+		}
+		return got
+	}
+	if got := received("x"); !slices.Contains(got, types.MsgBidPlaced) || !slices.Contains(got, types.MsgTurnChanged) {
+		t.Errorf("expected the spectator to see the bid and the turn, got %v", got)
+	}
+
+	fs.outputs = nil
+	g.SyncPlayer("x")
+	if got := received("x"); !slices.Equal(got, []types.MessageType{types.MsgGameStarted, types.MsgStateSync}) {
+		t.Fatalf("expected the spectator to be synced without a hand, got %v", got)
+	}
+	var view PlayerView
+	json.Unmarshal(fs.outputs[len(fs.outputs)-1].Env.Payload, &view)
+	if view.Hand != nil || view.Bids["b"] != 1 {
+		t.Errorf("expected the table view, got %+v", view)
+	}
+}
+
+func TestTurnChangedCarriesLegalMoves(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	rules := DefaultRules()
+	rules.CardsPerRound = 2
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+
+	// The latest turn_changed each player received
+	turns := func() map[types.PlayerID]TurnChanged {
+		got := make(map[types.PlayerID]TurnChanged)
+		for _, out := range fs.outputs {
+			if out.Env.Type != types.MsgTurnChanged {
+				continue
+			}
+			var turn TurnChanged
+			json.Unmarshal(out.Env.Payload, &turn)
+			for _, id := range out.Players {
+				got[id] = turn
+			}
+		}
+		fs.outputs = nil
+		return got
+	}
+	turns()
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	got := turns()
+	if len(got["a"].LegalBids) != 3 || len(got["b"].LegalBids) != 0 {
+		t.Errorf("expected only a to be sent bids 0 to 2, got %+v", got)
+	}
+
+	g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 0}))
+	got = turns()
+	if len(got["b"].LegalCards) != 2 || got["a"].LegalCards != nil {
+		t.Errorf("expected only b to be sent their playable cards, got %+v", got)
+	}
+}
+
+func TestTrickHistory(t *testing.T) {
+	fs := newFakeSession("a", "b")
+	rules := DefaultRules()
+	rules.MaxRounds = 1
+	rules.CardsPerRound = 2
+
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 1}))
+
+	if g.ViewFor("a").LastTrick != nil {
+		t.Fatalf("expected no last trick before any trick is played")
+	}
+
+	lead := legalCard(g, g.Players["b"])
+	g.HandleGameInput(input(fs, "b", types.MsgPlayCard, lead))
+	g.HandleGameInput(input(fs, "a", types.MsgPlayCard, legalCard(g, g.Players["a"])))
+
+	last := g.ViewFor("a").LastTrick
+	if last == nil || last.Leader != "b" || len(last.Plays) != 2 || last.Plays[0].Card != lead {
+		t.Fatalf("expected last trick led by b with %s, got %+v", lead, last)
+	}
+
+	fs.outputs = nil
+	g.Pause(time.Now())
+	if err := g.HandleGameInput(input(fs, "a", types.MsgGetTrickHistory, nil)); err != nil {
+		t.Fatalf("expected history while paused, got %v", err)
+	}
+
+	var history TrickHistory
+	out := fs.outputs[len(fs.outputs)-1]
+	json.Unmarshal(out.Env.Payload, &history)
+	if out.Env.Type != types.MsgTrickHistory || len(history.Tricks) != 1 || history.Tricks[0].Winner != last.Winner {
+		t.Errorf("expected one trick in history, got %s", out.Env.Payload)
+	}
+
+	future := Round(3)
+	if err := g.HandleGameInput(input(fs, "a", types.MsgGetTrickHistory, TrickHistoryRequest{Round: &future})); err == nil {
+		t.Errorf("expected error for a round not played yet")
+	}
+}
+
+func TestUndo(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	if err := g.HandleGameInput(input(fs, "c", types.MsgUndoRequest, nil)); err == nil {
+		t.Fatalf("expected undo of someone else's move to be rejected")
+	}
+
+	// Denied undo leaves the bid in place
+	g.HandleGameInput(input(fs, "b", types.MsgUndoRequest, nil))
+	g.HandleGameInput(input(fs, "c", types.MsgUndoResponse, UndoResponse{Approve: false}))
+	if g.undo != nil || g.Players["b"].Bid == nil {
+		t.Fatalf("expected denied undo to keep b's bid")
+	}
+
+	// The next move cancels a pending vote
+	g.HandleGameInput(input(fs, "b", types.MsgUndoRequest, nil))
+	g.HandleGameInput(input(fs, "c", types.MsgMakeBid, MakeBid{Bid: 1}))
+	if g.undo != nil || g.Players["b"].Bid == nil {
+		t.Fatalf("expected c's bid to cancel the undo")
+	}
+	g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 1}))
+
+	player := g.Players[g.state.TurnPlayer]
+	hand := append(Hand(nil), player.Cards...)
+	g.HandleGameInput(input(fs, player.ID, types.MsgPlayCard, legalCard(g, player)))
+	if len(g.state.Table) != 1 {
+		t.Fatalf("expected one card on the table")
+	}
+
+	fs.outputs = nil
+	g.HandleGameInput(input(fs, player.ID, types.MsgUndoRequest, nil))
+	for _, id := range g.allPlayerIDs() {
+		if id != player.ID {
+			g.HandleGameInput(input(fs, id, types.MsgUndoResponse, UndoResponse{Approve: true}))
+		}
+	}
+
+	if len(g.state.Table) != 0 || len(g.cardstack) != 0 || len(player.Cards) != len(hand) {
+		t.Errorf("expected the play to be rolled back, table %v", g.state.Table)
+	}
+	if g.state.TurnPlayer != player.ID || g.cycler.keys[g.cycler.index] != player.ID {
+		t.Errorf("expected %s to be on turn again, got %s", player.ID, g.state.TurnPlayer)
+	}
+	if out := fs.outputs[len(fs.outputs)-1]; out.Env.Type != types.MsgStateSync {
+		t.Errorf("expected a state_sync after the rollback, got %s", out.Env.Type)
+	}
+}
+
+func TestUndoVotersAndPause(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	rules := DefaultRules()
+	rules.TurnSeconds = 10
+	g := NewGame(fs, rules, fs.seats, "a")
+	g.Start()
+
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "b", types.MsgUndoRequest, nil))
+
+	// Spectators have no say, not even to deny
+	spectator := types.GameInput{
+		Player: &types.Player{ID: "x", PlayerName: "x"},
+		Env:    types.Envelope{Type: types.MsgUndoResponse, Payload: []byte(`{"approve":false}`)},
+	}
+	err := g.HandleGameInput(spectator)
+	if actionErr, ok := err.(*types.ActionError); !ok || actionErr.Code != types.ErrNotSeated {
+		t.Fatalf("expected a spectator vote to be rejected, got %v", err)
+	}
+	if g.undo == nil || g.undo.approvals["x"] {
+		t.Fatalf("expected the spectator to leave the vote alone")
+	}
+
+	// A rollback while paused stays paused and gives b a fresh turn on resume
+	g.HandleGameInput(input(fs, "a", types.MsgUndoResponse, UndoResponse{Approve: true}))
+	now := time.Now()
+	g.Pause(now)
+	delete(fs.players, "c")
+	g.Tick(now.Add(time.Minute))
+	if g.undo != nil || g.Players["b"].Bid != nil {
+		t.Fatalf("expected the undo to pass once c left")
+	}
+	if !g.state.Paused || g.state.Deadline != nil {
+		t.Fatalf("expected the game to stay paused after the rollback")
+	}
+	later := now.Add(time.Hour)
+	g.Resume(later)
+	if g.state.Deadline == nil || g.state.Deadline.Before(later.Add(9*time.Second)) {
+		t.Errorf("expected the turn timer to restart, got %v", g.state.Deadline)
+	}
+}
+
+func TestVotesWaitOnlyOnConnectedPlayers(t *testing.T) {
+	fs := newFakeSession("a", "b", "c")
+	g := NewGame(fs, DefaultRules(), fs.seats, "a")
+	g.Start()
+
+	// c left, so b's approval is enough
+	delete(fs.players, "c")
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "b", types.MsgUndoRequest, nil))
+	g.HandleGameInput(input(fs, "a", types.MsgUndoResponse, UndoResponse{Approve: true}))
+	if g.undo != nil || g.Players["b"].Bid != nil {
+		t.Fatalf("expected the undo to pass without c")
+	}
+
+	// The holdout leaving mid vote settles it on the next tick
+	fs.players["c"] = &types.Player{ID: "c", PlayerName: "c"}
+	g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 1}))
+	g.HandleGameInput(input(fs, "b", types.MsgUndoRequest, nil))
+	g.HandleGameInput(input(fs, "a", types.MsgUndoResponse, UndoResponse{Approve: true}))
+	if g.undo == nil {
+		t.Fatalf("expected the undo to wait for c")
+	}
+	delete(fs.players, "c")
+	g.Tick(time.Now())
+	if g.undo != nil || g.Players["b"].Bid != nil {
+		t.Errorf("expected the undo to pass once c left")
+	}
+}
+
+func TestClaim(t *testing.T) {
+	// Two card rounds with spades as trump and b on lead
+	setup := func(b, a []string) (*fakeSession, *Game) {
+		fs := newFakeSession("a", "b")
+		rules := DefaultRules()
+		rules.MaxRounds = 2
+		rules.CardsPerRound = 2
+
+		g := NewGame(fs, rules, fs.seats, "a")
+		g.Start()
+		g.HandleGameInput(input(fs, "b", types.MsgMakeBid, MakeBid{Bid: 0}))
+		g.HandleGameInput(input(fs, "a", types.MsgMakeBid, MakeBid{Bid: 0}))
+
+		hand := func(cards []string) Hand {
+			out := make(Hand, len(cards))
+			for i, c := range cards {
+				out[i], _ = ParseCard(c)
+			}
+			return out
+		}
+		spades := Spade
+		g.state.TrumpSuit = &spades
+		g.Players["b"].Cards = hand(b)
+		g.Players["a"].Cards = hand(a)
+		fs.outputs = nil
+		return fs, g
+	}
+
+	wonBy := func(g *Game, id types.PlayerID) int {
+		n := 0
+		for _, trick := range g.tricks[0] {
+			if trick.Winner == id {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Top trumps take both tricks whatever a does
+	fs, g := setup([]string{"AS", "KS"}, []string{"2H", "3H"})
+	if err := g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 2})); err != nil {
+		t.Fatalf("expected claim to be accepted, got %v", err)
+	}
+	if g.state.Round != 1 || wonBy(g, "b") != 2 {
+		t.Fatalf("expected the round to be played out with b taking both tricks")
+	}
+	if fs.outputs[0].Env.Type != types.MsgClaimResolved {
+		t.Errorf("expected claim_resolved first, got %s", fs.outputs[0].Env.Type)
+	}
+	for _, trick := range g.tricks[0] {
+		for _, play := range trick.Plays {
+			if !play.Claimed {
+				t.Errorf("expected plays made for the claim to be marked, got %+v", play)
+			}
+		}
+	}
+
+	fs, g = setup([]string{"AS", "KS"}, []string{"2H", "3H"})
+	if err := g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 1})); err == nil {
+		t.Errorf("expected a claim no line reaches to be rejected")
+	}
+
+	// b only takes nothing if a trumps the ace, so the table decides
+	fs, g = setup([]string{"AH", "KC"}, []string{"2H", "3S"})
+	if err := g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 0})); err != nil {
+		t.Fatalf("expected claim to go to a vote, got %v", err)
+	}
+	if g.claim == nil || g.state.Round != 0 {
+		t.Fatalf("expected a pending claim")
+	}
+	g.HandleGameInput(input(fs, "a", types.MsgClaimResponse, ClaimResponse{Accept: true}))
+	if g.claim != nil || g.state.Round != 1 || wonBy(g, "b") != 0 || wonBy(g, "a") != 2 {
+		t.Fatalf("expected the accepted claim to be played out, tricks %+v", g.tricks[0])
+	}
+
+	fs, g = setup([]string{"AH", "KC"}, []string{"2H", "3S"})
+	g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 0}))
+	g.HandleGameInput(input(fs, "a", types.MsgClaimResponse, ClaimResponse{Accept: false}))
+	if g.claim != nil || len(g.Players["b"].Cards) != 2 {
+		t.Errorf("expected play to go on after a rejected claim")
+	}
+
+	// Spectators cannot answer a claim
+	fs, g = setup([]string{"AH", "KC"}, []string{"2H", "3S"})
+	g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 0}))
+	spectator := types.GameInput{
+		Player: &types.Player{ID: "x", PlayerName: "x"},
+		Env:    types.Envelope{Type: types.MsgClaimResponse, Payload: []byte(`{"accept":false}`)},
+	}
+	err := g.HandleGameInput(spectator)
+	if actionErr, ok := err.(*types.ActionError); !ok || actionErr.Code != types.ErrNotSeated {
+		t.Fatalf("expected a spectator answer to be rejected, got %v", err)
+	}
+	if g.claim == nil || g.claim.approvals["x"] {
+		t.Fatalf("expected the claim to still wait on a")
+	}
+
+	// Nobody answering is not consent
+	fs.players["a"] = &types.Player{ID: "a", PlayerName: "a", Bot: true}
+	g.Tick(time.Now())
+	if g.claim != nil || len(g.Players["b"].Cards) != 2 {
+		t.Errorf("expected the claim to be rejected once only a bot was left to answer")
+	}
+	if err := g.HandleGameInput(input(fs, "b", types.MsgClaim, Claim{Tricks: 0})); err == nil || g.claim != nil {
+		t.Errorf("expected an unproven claim against a bot to be refused")
+	}
+}
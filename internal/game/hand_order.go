@@ -0,0 +1,88 @@
+package game
+
+// Order of the cards in a hand, decided on the server so every client shows the same
+
+import "sort"
+
+type HandOrder struct {
+	TrumpFirst       bool `json:"trumpFirst"`       // trump suit leads once it is known
+	AlternateColours bool `json:"alternateColours"` // no two suits of the same colour side by side where possible
+	HighFirst        bool `json:"highFirst"`        // aces first within a suit
+}
+
+func DefaultHandOrder() HandOrder {
+	return HandOrder{TrumpFirst: true, AlternateColours: true, HighFirst: true}
+}
+
+func (s Suit) red() bool {
+	return s == Heart || s == Diamond
+}
+
+// Suits held in the hand in the order they are shown
+func suitOrder(hand Hand, order HandOrder, trump *Suit) []Suit {
+	var remaining []Suit
+	for suit := Spade; suit <= Club; suit++ {
+		for _, card := range hand {
+			if card.Suit == suit {
+				remaining = append(remaining, suit)
+				break
+			}
+		}
+	}
+
+	suits := make([]Suit, 0, len(remaining))
+	take := func(i int) {
+		suits = append(suits, remaining[i])
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+
+	if order.TrumpFirst && trump != nil {
+		for i, suit := range remaining {
+			if suit == *trump {
+				take(i)
+				break
+			}
+		}
+	}
+
+	for len(remaining) > 0 {
+		next := 0
+		if order.AlternateColours && len(suits) > 0 {
+			last := suits[len(suits)-1]
+			for i, suit := range remaining {
+				if suit.red() != last.red() {
+					next = i
+					break
+				}
+			}
+		}
+		take(next)
+	}
+	return suits
+}
+
+func sortHand(hand Hand, order HandOrder, trump *Suit) {
+	position := make(map[Suit]int, 4)
+	for i, suit := range suitOrder(hand, order, trump) {
+		position[suit] = i
+	}
+
+	sort.SliceStable(hand, func(i, j int) bool {
+		a, b := hand[i], hand[j]
+		if a.Suit != b.Suit {
+			return position[a.Suit] < position[b.Suit]
+		}
+		if order.HighFirst {
+			return a.Rank > b.Rank
+		}
+		return a.Rank < b.Rank
+	})
+}
+
+// Called when trump is decided mid round
+func (g *Game) resortHands() {
+	for _, player := range g.Players {
+		sortHand(player.Cards, g.params.handOrder, g.state.TrumpSuit)
+		g.sendCardsToPlayer(player)
+	}
+}
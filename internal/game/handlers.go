@@ -4,25 +4,25 @@ package game
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	t "github.com/B33Boy/Judgement/internal/types"
 )
 
-func (g *Game) handleBid(input t.GameInput) {
-	if input.Env.Type != t.MsgMakeBid {
-		log.Println("Invalid message type, \"make_bid\" expected")
-		return
+func (g *Game) handleBid(input t.GameInput) error {
+	curPlayer, err := g.verifyPlayerTurn(input.Player.ID)
+	if err != nil {
+		return err
 	}
 
-	curPlayer := g.Players[input.Player.ID]
-	if err := g.verifyPlayerTurn(curPlayer); err != nil {
-		log.Printf("HandleBid: %v", err)
-		return
+	snap := g.snapshot(curPlayer.ID)
+	if err := g.recordBid(curPlayer, input); err != nil {
+		return err
 	}
-
-	g.recordBid(curPlayer, input)
+	g.commitMove(snap)
+	g.sendBidPlaced(curPlayer, *curPlayer.Bid)
 
 	g.state.TurnPlayer = g.cyclePlayer()
 
@@ -30,135 +30,129 @@ func (g *Game) handleBid(input t.GameInput) {
 		g.changeState(BiddingDone)
 	}
 
-	g.broadcastGameState()
+	g.resetTurnTimer(time.Now())
+	g.sendTurnChanged()
+	return nil
 }
 
-func (g *Game) recordBid(curPlayer *GamePlayer, input t.GameInput) {
+func (g *Game) recordBid(curPlayer *GamePlayer, input t.GameInput) error {
 
 	var payload MakeBid
 	err := json.Unmarshal(input.Env.Payload, &payload)
 
 	if err != nil {
-		log.Println("Cannot unmarshall MakeBid")
-		return
+		return t.NewActionError(t.ErrInvalidPayload, "Cannot read bid")
 	}
 
-	// Logic to check if bid is possible
+	// Can bid anywhere from no tricks to every trick in the round
+	if payload.Bid < 0 || int(payload.Bid) > len(curPlayer.Cards) {
+		return t.NewActionError(t.ErrBidOutOfRange,
+			fmt.Sprintf("Bid must be between 0 and %d", len(curPlayer.Cards)))
+	}
 
 	curPlayer.Bid = &payload.Bid
+	g.state.Bids[curPlayer.ID] = payload.Bid
+	return nil
 }
 
-func (g *Game) handlePlay(input t.GameInput) {
-	// receive played card, send rejection message if not possible to play
-	if input.Env.Type != t.MsgPlayCard {
-		log.Println("Invalid message type, \"make_bid\" expected")
-		return
-	}
-
+func (g *Game) handlePlay(input t.GameInput) error {
 	// Get card from input
 	var playedCard Card
 	err := json.Unmarshal(input.Env.Payload, &playedCard)
 	if err != nil {
-		log.Println("Cannot unmarshal played card")
-		return
+		return t.NewActionError(t.ErrInvalidPayload, "Cannot read played card")
 	}
 
 	log.Printf("%v", playedCard.String())
 
 	// Get player from input and ensure that it is their turn
-	curPlayer := g.Players[input.Player.ID]
-	if err := g.verifyPlayerTurn(curPlayer); err != nil {
-		// g.sendInvalidMove(input.Player.ID, "Not your turn")
-		log.Printf("HandlePlay: %v", err)
-		return
+	curPlayer, err := g.verifyPlayerTurn(input.Player.ID)
+	if err != nil {
+		return err
 	}
 
-	// For rounds where we start of with no trump suit
-	g.handleNoTrumpSuit(playedCard.Suit)
+	if !curPlayer.Cards.Contains(playedCard) {
+		return t.NewActionError(t.ErrCardNotInHand, "You do not have that card")
+	}
 
-	// // check if card is playable
+	// check if card is playable
 	if !g.isCardPlayable(curPlayer, playedCard) {
-		// g.sendInvalidMove(input.Player.ID, "Card cannot be played")
-		log.Printf("Card not playable: %v", playedCard)
-		return
+		return t.NewActionError(t.ErrMustFollowSuit, "You must follow suit or play trump")
 	}
 
+	g.commitMove(g.snapshot(curPlayer.ID))
+	g.applyPlay(curPlayer, playedCard)
+	return nil
+}
+
+// Plays a card that already passed every check
+func (g *Game) applyPlay(curPlayer *GamePlayer, playedCard Card) {
+	// For rounds where we start of with no trump suit
+	trumpChanged := g.handleNoTrumpSuit(playedCard.Suit)
+
 	// Play card
 	g.playCard(curPlayer, playedCard)
-	g.sendCardsToPlayer(curPlayer)
+	if trumpChanged {
+		g.resortHands() // resends every hand
+	} else {
+		g.sendCardsToPlayer(curPlayer)
+	}
+	g.sendCardPlayed(curPlayer, playedCard)
 
 	g.state.TurnPlayer = g.cyclePlayer()
 
 	if g.cycler.CompletedCycle() {
-		g.changeState(PlayingDone)
+		g.resolveTrick()
 	}
-	// g.broadcastCardPlayed(input.Player.ID, input.Card)
-	g.broadcastGameState()
-}
-
-func (g *Game) handleResolution(input t.GameInput) {
-	// Update scores and send to frontend
-	// call StartFrom() to start from the winning player
-	// call UpdateRound() here
-	g.updateRound()
+	g.resetTurnTimer(time.Now())
+	g.sendTurnChanged()
 }
 
-func (g *Game) updateRound() {
-	// Run this after every player turn, it will only update round when we return back to first player
-	completed := g.cycler.CompletedCycle()
-	if !completed {
-		log.Println("Player cycle not completed, not updating round yet")
-		return
-	}
-	g.state.Round++
-
-	if g.state.Round > g.params.maxRounds {
-		// state change to finished game
-		g.changeState(GameDone)
+func (g *Game) verifyPlayerTurn(id t.PlayerID) (*GamePlayer, error) {
+	player, ok := g.Players[id]
+	if !ok {
+		return nil, t.NewActionError(t.ErrNotSeated, "You are not seated in this game")
 	}
-}
-
-func (g *Game) verifyPlayerTurn(player *GamePlayer) error {
 	if player.ID != g.state.TurnPlayer {
-		log.Printf("It is %s's turn!\n", g.Players[g.state.TurnPlayer].PlayerName)
-		return errors.New("Incorrect player turn")
+		return nil, t.NewActionError(t.ErrNotYourTurn,
+			fmt.Sprintf("It is %s's turn", g.Players[g.state.TurnPlayer].PlayerName))
 	}
-	return nil
+	return player, nil
 }
 
 func (g *Game) isCardPlayable(player *GamePlayer, card Card) bool {
+	return canPlay(player.Cards, card, g.cardstack, g.state.TrumpSuit)
+}
+
+// Follow suit rules on their own, so claims can be checked without a Game
+func canPlay(hand Hand, card Card, cardstack []Card, trump *Suit) bool {
 
 	// If there are no cards on the cardstack, any card is playable
-	if len(g.cardstack) == 0 {
+	if len(cardstack) == 0 {
 		return true
 	}
-	curTop := g.cardstack[len(g.cardstack)-1]
+	// Follow the suit led, the same suit trickWinner judges the trick by
+	lead := cardstack[0]
 
-	trump := g.state.TrumpSuit
 	hasTrump := trump != nil
 
 	hasLegalAlternative := false
 
-	for _, playerCard := range player.Cards {
-		if sameSuit(playerCard, curTop) || (hasTrump && playerCard.Suit == *trump) {
+	for _, playerCard := range hand {
+		if sameSuit(playerCard, lead) || (hasTrump && playerCard.Suit == *trump) {
 
 			hasLegalAlternative = true
 
 			// If the played card is one of them, allow
 			if playerCard.Equals(card) {
-				log.Println("Card follows suit or trump")
 				return true
 			}
 		}
 	}
 
-	// If no legal alternatives exist, player can play anything
-	if !hasLegalAlternative {
-		log.Println("No matching suit or trump, any card allowed")
-		return true
-	}
-
-	return false // had legal option but player didn't use it
+	// If no legal alternatives exist, player can play anything,
+	// otherwise they had a legal option but didn't use it
+	return !hasLegalAlternative
 }
 
 func (g *Game) playCard(player *GamePlayer, card Card) {
@@ -177,13 +171,16 @@ func (g *Game) removeCardFromPlayer(player *GamePlayer, playedCard Card) {
 
 func (g *Game) addCardToTable(player *GamePlayer, card Card) {
 	g.cardstack = append(g.cardstack, card)
+	g.recordPlay(player, card)
 	g.state.Table[player.ID] = &card
 }
 
-func (g *Game) handleNoTrumpSuit(suit Suit) {
+func (g *Game) handleNoTrumpSuit(suit Suit) bool {
 	trump := g.state.TrumpSuit
 	if trump == nil {
 		// Make current card (initial) the trump suit
 		g.state.TrumpSuit = &suit
+		return true
 	}
+	return false
 }
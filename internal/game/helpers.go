@@ -26,6 +26,17 @@ func (g *Game) allPlayerIDs() []t.PlayerID {
 	return all_ids
 }
 
+// Seated players and everyone in the session watching the table
+func (g *Game) audienceIDs() []t.PlayerID {
+	ids := g.allPlayerIDs()
+	for id := range g.members() {
+		if _, seated := g.Players[id]; !seated {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func (g *Game) changeState(e Event) {
 	g.trigger(e)
 	g.state.State = g.sm.state
@@ -0,0 +1,119 @@
+package game
+
+// Text notation of cards. Cards are written in the long form "SPADE-ACE",
+// the short form "AS" or "10H" is accepted as well.
+
+import (
+	"fmt"
+	"strings"
+)
+
+var suitNames = [...]string{"SPADE", "HEART", "DIAMOND", "CLUB"}
+
+var rankNames = [...]string{
+	"", "", "2", "3", "4", "5", "6", "7",
+	"8", "9", "10", "JACK", "QUEEN", "KING", "ACE",
+}
+
+var suitLetters = map[string]Suit{"S": Spade, "H": Heart, "D": Diamond, "C": Club}
+
+var rankLetters = map[string]Rank{"T": Ten, "J": Jack, "Q": Queen, "K": King, "A": Ace}
+
+func ParseSuit(s string) (Suit, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if suit, ok := suitLetters[s]; ok {
+		return suit, nil
+	}
+	for suit, name := range suitNames {
+		if s == name {
+			return Suit(suit), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown suit %q", s)
+}
+
+func ParseRank(s string) (Rank, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if rank, ok := rankLetters[s]; ok {
+		return rank, nil
+	}
+	for rank := Two; rank <= Ace; rank++ {
+		if s == rankNames[rank] {
+			return rank, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rank %q", s)
+}
+
+// Accepts "SPADE-ACE", "AS" and "10H", case insensitive
+func ParseCard(s string) (Card, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	if suit, rank, ok := strings.Cut(s, "-"); ok {
+		return parseCardParts(suit, rank, s)
+	}
+	if len(s) < 2 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	return parseCardParts(s[len(s)-1:], s[:len(s)-1], s)
+}
+
+func parseCardParts(suit, rank, card string) (Card, error) {
+	var c Card
+	var err error
+	if c.Suit, err = ParseSuit(suit); err != nil {
+		return Card{}, fmt.Errorf("invalid card %q: %w", card, err)
+	}
+	if c.Rank, err = ParseRank(rank); err != nil {
+		return Card{}, fmt.Errorf("invalid card %q: %w", card, err)
+	}
+	return c, nil
+}
+
+func (s Suit) MarshalText() ([]byte, error) {
+	if !s.Valid() {
+		return nil, fmt.Errorf("unknown suit %d", s)
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *Suit) UnmarshalText(text []byte) error {
+	suit, err := ParseSuit(string(text))
+	if err != nil {
+		return err
+	}
+	*s = suit
+	return nil
+}
+
+func (r Rank) MarshalText() ([]byte, error) {
+	if !r.Valid() {
+		return nil, fmt.Errorf("unknown rank %d", r)
+	}
+	return []byte(r.String()), nil
+}
+
+func (r *Rank) UnmarshalText(text []byte) error {
+	rank, err := ParseRank(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rank
+	return nil
+}
+
+func (c Card) MarshalText() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(c.String()), nil
+}
+
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
@@ -1,9 +1,80 @@
 package game
 
+import (
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type PlayerHand struct {
+	Cards Hand `json:"cards"`
+}
+
 type MakeBid struct {
 	Bid Bid `json:"bid"`
 }
 
-type InvalidActionPayload struct {
-	Message string `json:"message"`
+type GameEndReason string
+
+const (
+	GameCompleted GameEndReason = "completed"
+	GameAbandoned GameEndReason = "abandoned"
+)
+
+type GameEndPayload struct {
+	GameID       string               `json:"gameId"` // for looking the game up once it is archived
+	Reason       GameEndReason        `json:"reason"`
+	RoundsPlayed int                  `json:"roundsPlayed"`
+	Scores       map[t.PlayerID]Score `json:"scores"` // totals of every scored round
+}
+
+// Incremental updates sent as the game progresses, state_sync is only
+// used to bring a player up to date on join or resync
+
+type RoundStarted struct {
+	Round          Round      `json:"round"`
+	Dealer         t.PlayerID `json:"dealer"`
+	CardsPerPlayer int        `json:"cardsPerPlayer"`
+}
+
+type BidPlaced struct {
+	Player t.PlayerID `json:"player"`
+	Bid    Bid        `json:"bid"`
+}
+
+type CardPlayed struct {
+	Player    t.PlayerID `json:"player"`
+	Card      Card       `json:"card"`
+	TrumpSuit *Suit      `json:"trumpSuit"` // set by the first card of a no trump round
+}
+
+type TrickWon struct {
+	Winner   t.PlayerID `json:"winner"`
+	HandsWon int        `json:"handsWon"` // tricks the winner has taken this round
+}
+
+type RoundScored struct {
+	Round    Round                `json:"round"`
+	Bids     map[t.PlayerID]Bid   `json:"bids"`
+	HandsWon map[t.PlayerID]int   `json:"handsWon"`
+	Scores   map[t.PlayerID]Score `json:"scores"` // this round only
+	Totals   map[t.PlayerID]Score `json:"totals"`
+}
+
+// Sent a little after round_scored, never for deals too large to solve
+// during the game or for the last round
+type RoundSolved struct {
+	Round       Round              `json:"round"`
+	DoubleDummy map[t.PlayerID]int `json:"doubleDummy"` // tricks each player could have taken with perfect play
+}
+
+type TurnChanged struct {
+	Player   t.PlayerID `json:"player"`
+	State    State      `json:"state"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Paused   bool       `json:"paused"`
+
+	// Only in the copy sent to the player on turn
+	LegalCards []Card `json:"legalCards,omitempty"`
+	LegalBids  []Bid  `json:"legalBids,omitempty"`
 }
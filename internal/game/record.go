@@ -0,0 +1,111 @@
+package game
+
+// Everything needed to go over a game once it is over
+
+import (
+	"log"
+	"maps"
+	"slices"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Positions searched per player when a round is scored, larger deals
+// are left for the archive to solve once the game is over
+const roundSolveLimit = 200_000
+
+type RoundRecord struct {
+	Round    Round                `json:"round"`
+	Dealer   t.PlayerID           `json:"dealer"`
+	Deal     Deal                 `json:"deal"` // the leader bids first
+	Bids     map[t.PlayerID]Bid   `json:"bids"`
+	Tricks   []Trick              `json:"tricks"`
+	HandsWon map[t.PlayerID]int   `json:"handsWon"`
+	Scores   map[t.PlayerID]Score `json:"scores"`
+
+	// Tricks each player could have taken with perfect play, nil until solved
+	DoubleDummy map[t.PlayerID]int `json:"doubleDummy,omitempty"`
+}
+
+type GameRecord struct {
+	ID      string                `json:"id"`
+	Players map[t.PlayerID]string `json:"players"` // names they played under
+	Reason  GameEndReason         `json:"reason"`
+	Rounds  []RoundRecord         `json:"rounds"` // scored rounds only
+	Totals  map[t.PlayerID]Score  `json:"totals"`
+}
+
+// Called once the hands are dealt and sorted
+func (g *Game) recordDeal() {
+	deal := Deal{
+		Seats:  g.cycler.Seats(),
+		Hands:  make(map[t.PlayerID]Hand, len(g.Players)),
+		Trump:  g.state.TrumpSuit,
+		Leader: g.state.TurnPlayer,
+	}
+	for id, player := range g.Players {
+		deal.Hands[id] = slices.Clone(player.Cards)
+	}
+
+	g.rounds[g.state.Round] = RoundRecord{
+		Round:  g.state.Round,
+		Dealer: g.state.Dealer,
+		Deal:   deal,
+	}
+}
+
+// Called once the round is scored
+func (g *Game) recordRound() {
+	round := g.state.Round
+	record := &g.rounds[round]
+
+	record.Bids = maps.Clone(g.state.Bids)
+	record.Tricks = g.tricks[round]
+	record.HandsWon = maps.Clone(g.state.HandsWon)
+	record.Scores = make(map[t.PlayerID]Score, len(g.Players))
+	for id := range g.Players {
+		record.Scores[id] = g.scores[id][round]
+	}
+
+	// Solved off the run loop, Tick picks the result up
+	go func(deal Deal) {
+		dd, err := SolveDoubleDummy(deal, roundSolveLimit)
+		if err != nil {
+			log.Printf("Round %d left unsolved: %v", round, err)
+			return
+		}
+		g.solved <- roundSolution{round: round, tricks: dd}
+	}(record.Deal)
+}
+
+type roundSolution struct {
+	round  Round
+	tricks map[t.PlayerID]int
+}
+
+// Keeps and announces the rounds solved since the last tick
+func (g *Game) collectSolutions() {
+	for {
+		select {
+		case solution := <-g.solved:
+			g.rounds[solution.round].DoubleDummy = solution.tricks
+			g.broadcast(t.MsgRoundSolved, RoundSolved{Round: solution.round, DoubleDummy: solution.tricks})
+		default:
+			return
+		}
+	}
+}
+
+func (g *Game) Record() GameRecord {
+	record := GameRecord{
+		ID:      g.ID,
+		Players: make(map[t.PlayerID]string, len(g.Players)),
+		Reason:  g.endReason,
+		Rounds:  slices.Clone(g.rounds[:g.scored]),
+		Totals:  g.Totals(),
+	}
+	for id, player := range g.Players {
+		record.Players[id] = player.PlayerName
+	}
+	return record
+}
@@ -0,0 +1,217 @@
+package game
+
+// Post-game review: every bid and card of a finished game is compared with
+// the best alternative the bot finds, the costliest differences are blunders
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const (
+	reviewSamples     = 300 // deals simulated per bid
+	blundersPerPlayer = 3
+)
+
+type DecisionKind string
+
+const (
+	DecisionBid  DecisionKind = "bid"
+	DecisionCard DecisionKind = "card"
+)
+
+// A single bid or card, valued in points the player could expect from it
+type Decision struct {
+	Kind   DecisionKind `json:"kind"`
+	Round  Round        `json:"round"`
+	Trick  int          `json:"trick"` // card decisions only
+	Player t.PlayerID   `json:"player"`
+
+	Bid      *Bid  `json:"bid,omitempty"`
+	BestBid  *Bid  `json:"bestBid,omitempty"`
+	Card     *Card `json:"card,omitempty"`
+	BestCard *Card `json:"bestCard,omitempty"`
+
+	Value     float64 `json:"value"`
+	BestValue float64 `json:"bestValue"`
+	Loss      float64 `json:"loss"` // BestValue - Value, never negative
+}
+
+type PlayerReview struct {
+	Decisions int        `json:"decisions"`
+	TotalLoss float64    `json:"totalLoss"`
+	Blunders  []Decision `json:"blunders"` // biggest losses first
+}
+
+type GameReview struct {
+	GameID    string                       `json:"gameId"`
+	Players   map[t.PlayerID]*PlayerReview `json:"players"`
+	Decisions []Decision                   `json:"decisions"` // in the order they were made
+}
+
+// Replays the record decision by decision. Bids are valued by simulating the
+// unseen hands like coach mode does, cards by playing the real hands out
+// with the bot, so the result is the same every time.
+func ReviewGame(record GameRecord) GameReview {
+	review := GameReview{
+		GameID:  record.ID,
+		Players: make(map[t.PlayerID]*PlayerReview, len(record.Players)),
+	}
+	for id := range record.Players {
+		review.Players[id] = &PlayerReview{Blunders: []Decision{}}
+	}
+
+	for _, round := range record.Rounds {
+		review.Decisions = append(review.Decisions, reviewBids(round)...)
+		review.Decisions = append(review.Decisions, reviewCards(round)...)
+	}
+
+	for _, decision := range review.Decisions {
+		player, ok := review.Players[decision.Player]
+		if !ok {
+			continue
+		}
+		player.Decisions++
+		player.TotalLoss += decision.Loss
+		if decision.Loss > 0 {
+			player.Blunders = append(player.Blunders, decision)
+		}
+	}
+	for _, player := range review.Players {
+		slices.SortStableFunc(player.Blunders, func(a, b Decision) int {
+			return cmp.Compare(b.Loss, a.Loss)
+		})
+		player.Blunders = player.Blunders[:min(len(player.Blunders), blundersPerPlayer)]
+	}
+	return review
+}
+
+// Bids in bidding order, from the leader round the table
+func reviewBids(round RoundRecord) []Decision {
+	deal := round.Deal
+	start := slices.Index(deal.Seats, deal.Leader)
+
+	var decisions []Decision
+	for i := range deal.Seats {
+		seat := (start + i) % len(deal.Seats)
+		id := deal.Seats[seat]
+
+		rng := rand.New(rand.NewSource(int64(round.Round)<<8 | int64(seat)))
+		advice := estimateTricks(deal.Hands[id], deal.Seats, id, deal.Leader, deal.Trump, reviewSamples, rng)
+
+		// Points expected from a bid, only an exact bid scores
+		value := func(bid Bid) float64 {
+			return advice.Chances[bid] * float64(scoreRound(bid, int(bid)))
+		}
+
+		bid := round.Bids[id]
+		best := Bid(0)
+		for b := range Bid(len(advice.Chances)) {
+			if value(b) > value(best) {
+				best = b
+			}
+		}
+
+		decisions = append(decisions, Decision{
+			Kind:      DecisionBid,
+			Round:     round.Round,
+			Player:    id,
+			Bid:       &bid,
+			BestBid:   &best,
+			Value:     value(bid),
+			BestValue: value(best),
+			Loss:      max(value(best)-value(bid), 0),
+		})
+	}
+	return decisions
+}
+
+// Every card a player chose, each legal alternative played out by the bot for
+// everyone. Cards the server played out for a claim are nobody's choice.
+func reviewCards(round RoundRecord) []Decision {
+	deal := round.Deal
+	hands := make(map[t.PlayerID]Hand, len(deal.Hands))
+	for id, hand := range deal.Hands {
+		hands[id] = slices.Clone(hand)
+	}
+	won := make(map[t.PlayerID]int, len(deal.Seats))
+	trump := deal.Trump
+
+	var decisions []Decision
+	for i, trick := range round.Tricks {
+		for j, play := range trick.Plays {
+			if !play.Claimed {
+				decisions = append(decisions, reviewCard(round, i, trick.Leader, trick.Plays[:j], play, hands, won, trump))
+			}
+
+			hands[play.Player] = removeCard(hands[play.Player], play.Card)
+			if trump == nil {
+				suit := play.Card.Suit
+				trump = &suit
+			}
+		}
+		won[trick.Winner]++
+	}
+	return decisions
+}
+
+func reviewCard(round RoundRecord, trickIndex int, leader t.PlayerID, trick []Play, play Play,
+	hands map[t.PlayerID]Hand, won map[t.PlayerID]int, trump *Suit) Decision {
+
+	id := play.Player
+	hand := hands[id]
+	stack := make([]Card, len(trick))
+	for i, p := range trick {
+		stack[i] = p.Card
+	}
+
+	// Everyone goes for tricks until they have made their bid
+	want := func(player t.PlayerID, playout map[t.PlayerID]int) bool {
+		return won[player]+playout[player] < int(round.Bids[player])
+	}
+
+	value := func(card Card) float64 {
+		rest := make(map[t.PlayerID]Hand, len(hands))
+		for player, h := range hands {
+			rest[player] = h
+		}
+		rest[id] = removeCard(hand, card)
+
+		cardTrump := trump
+		if cardTrump == nil {
+			cardTrump = &card.Suit
+		}
+		next := append(slices.Clone(trick), Play{Player: id, Card: card})
+		taken := botPlayout(round.Deal.Seats, rest, leader, next, cardTrump, want)
+		return float64(scoreRound(round.Bids[id], won[id]+taken[id]))
+	}
+
+	// The bot's own pick wins ties, so it only differs when it matters
+	best := botCard(hand, trick, trump, want(id, nil))
+	bestValue := value(best)
+	for _, card := range hand {
+		if !canPlay(hand, card, stack, trump) {
+			continue
+		}
+		if v := value(card); v > bestValue {
+			best, bestValue = card, v
+		}
+	}
+
+	played := play.Card
+	playedValue := value(played)
+	return Decision{
+		Kind:      DecisionCard,
+		Round:     round.Round,
+		Trick:     trickIndex,
+		Player:    id,
+		Card:      &played,
+		BestCard:  &best,
+		Value:     playedValue,
+		BestValue: bestValue,
+		Loss:      max(bestValue-playedValue, 0),
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+func TestReviewGame(t *testing.T) {
+	card := func(s string) Card {
+		c, _ := ParseCard(s)
+		return c
+	}
+
+	// b leads 2H into a's KH, then can't stop a taking the second trick.
+	// Leading AS makes spades trump and wins both, so b makes their bid.
+	record := GameRecord{
+		ID:      "game",
+		Players: map[types.PlayerID]string{"a": "A", "b": "B"},
+		Rounds: []RoundRecord{{
+			Round: 0,
+			Deal: Deal{
+				Seats:  []types.PlayerID{"a", "b"},
+				Hands:  map[types.PlayerID]Hand{"a": {card("KH"), card("3C")}, "b": {card("AS"), card("2H")}},
+				Leader: "b",
+			},
+			Bids: map[types.PlayerID]Bid{"a": 1, "b": 1},
+			Tricks: []Trick{
+				{Leader: "b", Plays: []Play{{Player: "b", Card: card("2H")}, {Player: "a", Card: card("KH")}}, Winner: "a"},
+				{Leader: "a", Plays: []Play{{Player: "a", Card: card("3C")}, {Player: "b", Card: card("AS")}}, Winner: "a"},
+			},
+		}},
+	}
+
+	review := ReviewGame(record)
+	if len(review.Decisions) != 6 {
+		t.Fatalf("expected 2 bids and 4 cards, got %d decisions", len(review.Decisions))
+	}
+	if review.Decisions[0].Player != "b" || review.Decisions[0].Kind != DecisionBid {
+		t.Errorf("expected the leader to bid first, got %+v", review.Decisions[0])
+	}
+
+	b := review.Players["b"]
+	if b.Decisions != 3 || len(b.Blunders) == 0 {
+		t.Fatalf("expected a blunder from b, got %+v", b)
+	}
+	worst := b.Blunders[0]
+	if worst.Kind != DecisionCard || worst.Trick != 0 || !worst.Card.Equals(card("2H")) ||
+		!worst.BestCard.Equals(card("AS")) || worst.Loss != 11 {
+		t.Errorf("expected leading 2H over AS to cost 11 points, got %+v", worst)
+	}
+
+	if again := ReviewGame(record); again.Players["b"].TotalLoss != b.TotalLoss {
+		t.Errorf("expected the same review every time, got %v and %v", b.TotalLoss, again.Players["b"].TotalLoss)
+	}
+
+	// Nobody is blamed for the cards a claim played out
+	for i := range record.Rounds[0].Tricks[1].Plays {
+		record.Rounds[0].Tricks[1].Plays[i].Claimed = true
+	}
+	if claimed := ReviewGame(record); len(claimed.Decisions) != 4 || claimed.Players["b"].Decisions != 2 {
+		t.Errorf("expected the claimed trick to be skipped, got %d decisions", len(claimed.Decisions))
+	}
+}
@@ -0,0 +1,127 @@
+package game
+
+// Round lifecycle (deal, tricks, scoring)
+
+import (
+	"log"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+// Deals a fresh round, the player after the dealer bids first
+func (g *Game) dealRound() {
+	hands := getHands(len(g.Players), g.params.cardsPerRound)
+
+	for i, id := range g.cycler.Seats() {
+		player := g.Players[id]
+		player.Cards = hands[i]
+		player.Bid = nil
+		sortHand(player.Cards, g.params.handOrder, nil)
+	}
+
+	g.state.TrumpSuit = nil
+	clear(g.state.Table)
+	clear(g.state.Bids)
+	clear(g.state.HandsWon)
+	clear(g.advice)
+	g.cardstack = g.cardstack[:0]
+	g.plays = g.plays[:0]
+
+	first, err := g.cycler.After(g.state.Dealer)
+	if err != nil {
+		log.Println("failed to find first bidder:", err)
+		g.cancel()
+		return
+	}
+
+	g.state.TurnPlayer = first
+	if err := g.cycler.StartFrom(first); err != nil {
+		log.Println("failed to start cycler:", err)
+	}
+	g.recordDeal()
+}
+
+// Called once every player has played to the trick
+func (g *Game) resolveTrick() {
+	winner := g.trickWinner()
+	g.recordTrick(winner)
+	g.state.HandsWon[winner]++
+	g.sendTrickWon(winner)
+
+	clear(g.state.Table)
+	g.cardstack = g.cardstack[:0]
+
+	// Winner leads the next trick
+	g.state.TurnPlayer = winner
+	g.cycler.StartFrom(winner)
+
+	if len(g.Players[winner].Cards) == 0 {
+		g.changeState(PlayingDone)
+	}
+}
+
+// Highest trump wins, otherwise the highest card of the suit led
+func (g *Game) trickWinner() t.PlayerID {
+	lead := g.cardstack[0]
+	trump := g.state.TrumpSuit
+
+	var winner t.PlayerID
+	var best *Card
+
+	for id, card := range g.state.Table {
+		if best == nil || beats(*card, *best, lead.Suit, trump) {
+			winner, best = id, card
+		}
+	}
+	return winner
+}
+
+func beats(card, best Card, lead Suit, trump *Suit) bool {
+	if trump != nil && card.Suit == *trump && best.Suit != *trump {
+		return true
+	}
+	if trump != nil && best.Suit == *trump && card.Suit != *trump {
+		return false
+	}
+	if card.Suit == best.Suit {
+		return higherRank(card, best)
+	}
+	return card.Suit == lead
+}
+
+// Scores the round and either deals the next one or ends the game
+func (g *Game) resolveRound() {
+	for id, player := range g.Players {
+		bid := Bid(0)
+		if player.Bid != nil {
+			bid = *player.Bid
+		}
+		g.scores[id][g.state.Round] = scoreRound(bid, g.state.HandsWon[id])
+	}
+	g.scored++
+	g.recordRound()
+	g.sendRoundScored()
+
+	if g.state.Round+1 >= g.params.maxRounds {
+		g.changeState(GameDone)
+		return
+	}
+
+	g.state.Round++
+	g.changeState(PlayingContinue)
+}
+
+func (g *Game) startNextRound() {
+	dealer, err := g.cycler.After(g.state.Dealer)
+	if err != nil {
+		log.Println("failed to rotate dealer:", err)
+		return
+	}
+	g.state.Dealer = dealer
+
+	g.dealRound()
+	g.sendRoundStarted()
+	for _, player := range g.Players {
+		g.sendCardsToPlayer(player)
+	}
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rules configurable by the session host before a game starts
+type Rules struct {
+	MaxRounds     Round `json:"maxRounds"`
+	CardsPerRound int   `json:"cardsPerRound"`
+	MinPlayers    int   `json:"minPlayers"`
+	MaxPlayers    int   `json:"maxPlayers"`
+	TurnSeconds   int   `json:"turnSeconds"` // 0 disables the turn timer
+
+	HandOrder HandOrder `json:"handOrder"`
+
+	// Practice aid, bidders are sent an estimate of the tricks their hand takes
+	Coach bool `json:"coach"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		MaxRounds:     14,
+		CardsPerRound: 7,
+		MinPlayers:    2,
+		MaxPlayers:    7,
+		HandOrder:     DefaultHandOrder(),
+	}
+}
+
+func (r Rules) Validate() error {
+	if r.MaxRounds < 1 || r.MaxRounds > 52 {
+		return errors.New("maxRounds must be between 1 and 52")
+	}
+	if r.CardsPerRound < 1 || r.CardsPerRound > 13 {
+		return errors.New("cardsPerRound must be between 1 and 13")
+	}
+	if r.MinPlayers < 2 {
+		return errors.New("minPlayers must be at least 2")
+	}
+	if r.TurnSeconds < 0 || r.TurnSeconds > 300 {
+		return errors.New("turnSeconds must be between 0 and 300")
+	}
+	if r.MaxPlayers < r.MinPlayers {
+		return errors.New("maxPlayers must not be less than minPlayers")
+	}
+	if r.MaxPlayers*r.CardsPerRound > len(newDeck()) {
+		return fmt.Errorf("not enough cards to deal %d to %d players", r.CardsPerRound, r.MaxPlayers)
+	}
+	return nil
+}
+
+// Checks that a game can be dealt for the given number of players
+func (r Rules) CheckPlayerCount(n int) error {
+	if n < r.MinPlayers {
+		return fmt.Errorf("at least %d players are needed to start", r.MinPlayers)
+	}
+	if n > r.MaxPlayers {
+		return fmt.Errorf("at most %d players can play", r.MaxPlayers)
+	}
+	return nil
+}
+
+func (r Rules) TurnTimeout() time.Duration {
+	return time.Duration(r.TurnSeconds) * time.Second
+}
@@ -0,0 +1,35 @@
+package game
+
+import "testing"
+
+func TestRulesValidate(t *testing.T) {
+	if err := DefaultRules().Validate(); err != nil {
+		t.Fatalf("default rules should be valid: %v", err)
+	}
+
+	rules := DefaultRules()
+	rules.MaxPlayers = 8 // 8 * 7 cards does not fit in a deck
+	if err := rules.Validate(); err == nil {
+		t.Errorf("expected error when deck cannot cover every player")
+	}
+
+	rules = DefaultRules()
+	rules.MinPlayers = 1
+	if err := rules.Validate(); err == nil {
+		t.Errorf("expected error for single player games")
+	}
+}
+
+func TestRulesCheckPlayerCount(t *testing.T) {
+	rules := DefaultRules()
+
+	if err := rules.CheckPlayerCount(1); err == nil {
+		t.Errorf("expected error for too few players")
+	}
+	if err := rules.CheckPlayerCount(rules.MaxPlayers + 1); err == nil {
+		t.Errorf("expected error for too many players")
+	}
+	if err := rules.CheckPlayerCount(4); err != nil {
+		t.Errorf("unexpected error for 4 players: %v", err)
+	}
+}
@@ -16,3 +16,11 @@ func NewScoreboard(playerCnt int, gamePlayers PlayerMap, maxRounds Round) Player
 	}
 	return scores
 }
+
+// Exact bids score 10 plus the bid, anything else scores nothing
+func scoreRound(bid Bid, handsWon int) Score {
+	if int(bid) != handsWon {
+		return 0
+	}
+	return Score(10 + bid)
+}
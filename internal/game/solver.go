@@ -0,0 +1,267 @@
+package game
+
+// Double-dummy solver: the most tricks each player can take with every hand
+// in view and everyone else playing against them. Alpha-beta over single
+// cards, with a transposition table on positions at the start of a trick.
+
+import (
+	"errors"
+	"math/bits"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+var errSolverLimit = errors.New("double-dummy search limit reached")
+
+// A round as it was dealt
+type Deal struct {
+	Seats  []t.PlayerID        `json:"seats"` // play order
+	Hands  map[t.PlayerID]Hand `json:"hands"`
+	Trump  *Suit               `json:"trump"` // nil when the first card picks trump
+	Leader t.PlayerID          `json:"leader"`
+}
+
+// Tricks each player can guarantee from the deal. limit caps the positions
+// searched per player so large deals give up instead of stalling.
+func SolveDoubleDummy(deal Deal, limit int) (map[t.PlayerID]int, error) {
+	leader := -1
+	for i, id := range deal.Seats {
+		if id == deal.Leader {
+			leader = i
+		}
+	}
+	if leader < 0 {
+		return nil, errors.New("leader is not seated")
+	}
+
+	tricks := make(map[t.PlayerID]int, len(deal.Seats))
+	for target, id := range deal.Seats {
+		s := newSolver(deal, target, limit)
+		n := bits.OnesCount64(s.hands[leader])
+		v, err := s.search(leader, -1, n+1)
+		if err != nil {
+			return nil, err
+		}
+		tricks[id] = v
+	}
+	return tricks, nil
+}
+
+// Cards are bits 0-51, thirteen per suit from two up
+func cardIndex(c Card) int {
+	return int(c.Suit)*13 + int(c.Rank-Two)
+}
+
+func cardAt(i int) Card {
+	return Card{Suit: Suit(i / 13), Rank: Rank(i%13) + Two}
+}
+
+func suitMask(s Suit) uint64 {
+	return uint64(1<<13-1) << (uint(s) * 13)
+}
+
+type ddPlay struct {
+	seat int
+	card int
+}
+
+// Position at the start of a trick, owner is the seat holding each card
+type ddKey struct {
+	owner  [52]int8
+	leader int8
+	trump  int8
+}
+
+// What is known about the target's tricks from a position
+type ddBounds struct {
+	lower, upper int
+}
+
+type ddSolver struct {
+	hands  []uint64 // by seat
+	owner  [52]int8 // -1 once played
+	trump  int8     // -1 until picked
+	target int
+	trick  []ddPlay
+	nodes  int
+	limit  int
+	table  map[ddKey]ddBounds
+}
+
+func newSolver(deal Deal, target, limit int) *ddSolver {
+	s := &ddSolver{
+		hands:  make([]uint64, len(deal.Seats)),
+		trump:  -1,
+		target: target,
+		limit:  limit,
+		table:  make(map[ddKey]ddBounds),
+	}
+	for i := range s.owner {
+		s.owner[i] = -1
+	}
+	if deal.Trump != nil {
+		s.trump = int8(*deal.Trump)
+	}
+	for seat, id := range deal.Seats {
+		for _, c := range deal.Hands[id] {
+			s.hands[seat] |= 1 << cardIndex(c)
+			s.owner[cardIndex(c)] = int8(seat)
+		}
+	}
+	return s
+}
+
+// Tricks the target takes from here, fail-soft within (alpha, beta)
+func (s *ddSolver) search(turn, alpha, beta int) (int, error) {
+	s.nodes++
+	if s.nodes > s.limit {
+		return 0, errSolverLimit
+	}
+
+	var key ddKey
+	bounds := ddBounds{upper: bits.OnesCount64(s.hands[turn])}
+	atStart := len(s.trick) == 0
+	if atStart {
+		if bounds.upper == 0 {
+			return 0, nil
+		}
+		key = ddKey{owner: s.owner, leader: int8(turn), trump: s.trump}
+		if known, ok := s.table[key]; ok {
+			bounds = known
+		}
+		if bounds.lower == bounds.upper || bounds.lower >= beta {
+			return bounds.lower, nil
+		}
+		if bounds.upper <= alpha {
+			return bounds.upper, nil
+		}
+		alpha, beta = max(alpha, bounds.lower), min(beta, bounds.upper)
+	}
+
+	maximising := turn == s.target
+	best := -1
+	if !maximising {
+		best = 1 << 30
+	}
+	a, b := alpha, beta
+
+	var table, tried uint64
+	for _, play := range s.trick {
+		table |= 1 << play.card
+	}
+
+	// Highest cards first, they settle tricks sooner
+	for legal := s.legal(turn); legal != 0; {
+		card := 63 - bits.LeadingZeros64(legal)
+		legal &^= 1 << card
+		if s.equivalent(card, tried, table) {
+			continue
+		}
+		tried |= 1 << card
+
+		v, err := s.play(turn, card, a, b)
+		if err != nil {
+			return 0, err
+		}
+
+		if maximising {
+			best = max(best, v)
+			a = max(a, best)
+		} else {
+			best = min(best, v)
+			b = min(b, best)
+		}
+		if a >= b {
+			break
+		}
+	}
+
+	if atStart {
+		switch {
+		case best <= alpha:
+			bounds.upper = best
+		case best >= beta:
+			bounds.lower = best
+		default:
+			bounds.lower, bounds.upper = best, best
+		}
+		s.table[key] = bounds
+	}
+	return best, nil
+}
+
+// Plays card for the seat on turn and searches on, then takes it back
+func (s *ddSolver) play(seat, card, alpha, beta int) (int, error) {
+	trump := s.trump
+	if s.trump < 0 {
+		s.trump = int8(card / 13) // first card of a no trump round picks trump
+	}
+	s.hands[seat] &^= 1 << card
+	s.owner[card] = -1
+	s.trick = append(s.trick, ddPlay{seat: seat, card: card})
+
+	var v int
+	var err error
+	if len(s.trick) == len(s.hands) {
+		trick := s.trick
+		winner := s.trickWinner()
+		won := 0
+		if winner == s.target {
+			won = 1
+		}
+		s.trick = s.trick[:0:0]
+		v, err = s.search(winner, alpha-won, beta-won)
+		v += won
+		s.trick = trick
+	} else {
+		v, err = s.search((seat+1)%len(s.hands), alpha, beta)
+	}
+
+	s.trick = s.trick[:len(s.trick)-1]
+	s.owner[card] = int8(seat)
+	s.hands[seat] |= 1 << card
+	s.trump = trump
+	return v, err
+}
+
+// A card next to one already tried, with only played cards between them,
+// does exactly the same
+func (s *ddSolver) equivalent(card int, tried, table uint64) bool {
+	for above := card + 1; above%13 != 0; above++ {
+		if tried&(1<<above) != 0 {
+			return true
+		}
+		if s.owner[above] >= 0 || table&(1<<above) != 0 {
+			return false
+		}
+	}
+	return false
+}
+
+// Same follow rule as canPlay: follow the suit led or play trump if you can
+func (s *ddSolver) legal(seat int) uint64 {
+	hand := s.hands[seat]
+	if len(s.trick) == 0 {
+		return hand
+	}
+	allowed := suitMask(Suit(s.trick[0].card / 13))
+	if s.trump >= 0 {
+		allowed |= suitMask(Suit(s.trump))
+	}
+	if hand&allowed != 0 {
+		return hand & allowed
+	}
+	return hand
+}
+
+func (s *ddSolver) trickWinner() int {
+	trump := Suit(s.trump)
+	lead := cardAt(s.trick[0].card).Suit
+	best := s.trick[0]
+	for _, play := range s.trick[1:] {
+		if beats(cardAt(play.card), cardAt(best.card), lead, &trump) {
+			best = play
+		}
+	}
+	return best.seat
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	types "github.com/B33Boy/Judgement/internal/types"
+)
+
+type seatPlay struct {
+	seat int
+	card Card
+}
+
+// Plain minimax over every legal card, no pruning
+func bruteForceTricks(hands []Hand, trick []seatPlay, trump *Suit, turn, target int) int {
+	if len(hands[turn]) == 0 {
+		return 0
+	}
+
+	stack := make([]Card, len(trick))
+	for i, play := range trick {
+		stack[i] = play.card
+	}
+
+	best := -1
+	for i, card := range hands[turn] {
+		if !canPlay(hands[turn], card, stack, trump) {
+			continue
+		}
+
+		next := slices.Clone(hands)
+		next[turn] = slices.Delete(slices.Clone(hands[turn]), i, i+1)
+		nextTrump := trump
+		if nextTrump == nil {
+			nextTrump = &card.Suit
+		}
+		nextTrick := append(slices.Clone(trick), seatPlay{seat: turn, card: card})
+
+		var v int
+		if len(nextTrick) == len(hands) {
+			winner := nextTrick[0]
+			for _, play := range nextTrick[1:] {
+				if beats(play.card, winner.card, nextTrick[0].card.Suit, nextTrump) {
+					winner = play
+				}
+			}
+			v = bruteForceTricks(next, nil, nextTrump, winner.seat, target)
+			if winner.seat == target {
+				v++
+			}
+		} else {
+			v = bruteForceTricks(next, nextTrick, nextTrump, (turn+1)%len(hands), target)
+		}
+
+		if best < 0 || (turn == target && v > best) || (turn != target && v < best) {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestSolveDoubleDummy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	seats := []types.PlayerID{"a", "b", "c"}
+
+	for i := range 30 {
+		deck := newDeck()
+		rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+
+		deal := Deal{Seats: seats, Hands: make(map[types.PlayerID]Hand), Leader: seats[i%len(seats)]}
+		hands := make([]Hand, len(seats))
+		for seat, id := range seats {
+			hands[seat] = Hand(deck[seat*4 : seat*4+4])
+			deal.Hands[id] = hands[seat]
+		}
+		// Every other deal has trump picked up front
+		if i%2 == 0 {
+			trump := Suit(rng.Intn(4))
+			deal.Trump = &trump
+		}
+
+		got, err := SolveDoubleDummy(deal, 1_000_000)
+		if err != nil {
+			t.Fatalf("deal %d: %v", i, err)
+		}
+		for seat, id := range seats {
+			want := bruteForceTricks(hands, nil, deal.Trump, i%len(seats), seat)
+			if got[id] != want {
+				t.Errorf("deal %d: expected %s to take %d tricks, solver says %d", i, id, want, got[id])
+			}
+		}
+	}
+}
+
+func TestSolveDoubleDummyLimit(t *testing.T) {
+	seats := []types.PlayerID{"a", "b", "c", "d"}
+	hands := getHands(len(seats), 13)
+
+	deal := Deal{Seats: seats, Hands: make(map[types.PlayerID]Hand), Leader: "a"}
+	for seat, id := range seats {
+		deal.Hands[id] = hands[seat]
+	}
+	if _, err := SolveDoubleDummy(deal, 100); err == nil {
+		t.Errorf("expected a full deal to run out of search budget")
+	}
+}
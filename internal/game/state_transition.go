@@ -21,6 +21,9 @@ func (g *Game) onStateChanged(from, to State) {
 
 	case StateBid:
 		log.Println("StateBid")
+		if from == StateResolution {
+			g.startNextRound()
+		}
 
 	case StatePlay:
 		g.cycler.StartFrom(g.state.TurnPlayer)
@@ -28,9 +31,11 @@ func (g *Game) onStateChanged(from, to State) {
 
 	case StateResolution:
 		log.Println("StateResolution")
+		g.resolveRound()
 
 	case StateGameOver:
 		log.Println("StateGameOver")
-		g.sendGameFinished()
+		g.state.Deadline = nil
+		g.sendGameFinished(g.endReason)
 	}
 }
@@ -0,0 +1,87 @@
+package game
+
+// Completed tricks of every round, kept for review during the game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type Play struct {
+	Player  t.PlayerID `json:"player"`
+	Card    Card       `json:"card"`
+	Claimed bool       `json:"claimed,omitempty"` // played out by the server to settle a claim
+}
+
+type Trick struct {
+	Leader t.PlayerID `json:"leader"`
+	Plays  []Play     `json:"plays"` // in play order
+	Winner t.PlayerID `json:"winner"`
+}
+
+type TrickHistoryRequest struct {
+	Round *Round `json:"round,omitempty"` // defaults to the current round
+}
+
+type TrickHistory struct {
+	Round  Round   `json:"round"`
+	Tricks []Trick `json:"tricks"`
+}
+
+func (g *Game) recordPlay(player *GamePlayer, card Card) {
+	g.plays = append(g.plays, Play{Player: player.ID, Card: card, Claimed: g.claiming})
+}
+
+func (g *Game) recordTrick(winner t.PlayerID) {
+	trick := Trick{
+		Leader: g.plays[0].Player,
+		Plays:  append([]Play(nil), g.plays...),
+		Winner: winner,
+	}
+	g.tricks[g.state.Round] = append(g.tricks[g.state.Round], trick)
+	g.plays = g.plays[:0]
+}
+
+// Last completed trick of the current round
+func (g *Game) lastTrick() *Trick {
+	tricks := g.tricks[g.state.Round]
+	if len(tricks) == 0 {
+		return nil
+	}
+	last := tricks[len(tricks)-1]
+	return &last
+}
+
+func (g *Game) handleTrickHistory(input t.GameInput) error {
+	var payload TrickHistoryRequest
+	if len(input.Env.Payload) > 0 {
+		if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+			return t.NewActionError(t.ErrInvalidPayload, "Invalid trick history request")
+		}
+	}
+
+	round := g.state.Round
+	if payload.Round != nil {
+		round = *payload.Round
+	}
+	if round < 0 || round > g.state.Round {
+		return t.NewActionError(t.ErrNotFound, fmt.Sprintf("Round must be between 0 and %d", g.state.Round))
+	}
+
+	history := TrickHistory{Round: round, Tricks: g.tricks[round]}
+	if history.Tricks == nil {
+		history.Tricks = []Trick{}
+	}
+
+	payloadOut, _ := json.Marshal(history)
+	g.emit(t.GameOutput{
+		Players: []t.PlayerID{input.Player.ID},
+		Env: t.Envelope{
+			Type:    t.MsgTrickHistory,
+			Payload: payloadOut,
+		},
+	})
+	return nil
+}
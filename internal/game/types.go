@@ -8,6 +8,7 @@ type PlayerMap map[t.PlayerID]*GamePlayer
 type GamePlayer struct {
 	ID         t.PlayerID
 	PlayerName string
+	Bot        bool // the game makes its moves
 	Bid        *Bid
 	Cards      Hand
 }
@@ -34,5 +35,6 @@ const (
 	PlayingContinue Event = "playing_continue"
 	PlayingDone     Event = "playing_done"
 	GameDone        Event = "game_done"
+	GameStopped     Event = "game_stopped"
 	RoundResolved   Event = "round_resolved"
 )
@@ -0,0 +1,209 @@
+package game
+
+// Taking back the last bid or card play once every other player agrees
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+const undoWindow = 15 * time.Second
+
+// Everything a single bid or card play can change within a round
+type snapshot struct {
+	mover     t.PlayerID
+	round     Round
+	state     GameState
+	smState   State
+	cycler    PlayerCycler
+	hands     map[t.PlayerID]Hand
+	bids      map[t.PlayerID]*Bid
+	cardstack []Card
+	plays     []Play
+	tricks    int // completed tricks this round
+}
+
+// Request by one player that every other connected player has to approve
+// in time, used for undos and unverified claims
+type tableVote struct {
+	player    t.PlayerID
+	deadline  time.Time
+	approvals map[t.PlayerID]bool
+}
+
+func newTableVote(player t.PlayerID, window time.Duration) *tableVote {
+	return &tableVote{
+		player:    player,
+		deadline:  time.Now().Add(window),
+		approvals: make(map[t.PlayerID]bool),
+	}
+}
+
+// Reports whether everyone but the requester still at the table has
+// approved. Players who left can't hold the vote up.
+func (v *tableVote) passed(g *Game) bool {
+	for id := range g.Players {
+		if id != v.player && !v.approvals[id] && g.connected(id) {
+			return false
+		}
+	}
+	return true
+}
+
+type UndoResponse struct {
+	Approve bool `json:"approve"`
+}
+
+type UndoRequested struct {
+	Player   t.PlayerID `json:"player"`
+	Deadline time.Time  `json:"deadline"`
+}
+
+type UndoResolved struct {
+	Player   t.PlayerID `json:"player"`
+	Approved bool       `json:"approved"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// Taken before a move is applied, kept once the move goes through
+func (g *Game) snapshot(mover t.PlayerID) *snapshot {
+	state := *g.state
+	state.Table = maps.Clone(g.state.Table)
+	state.Bids = maps.Clone(g.state.Bids)
+	state.HandsWon = maps.Clone(g.state.HandsWon)
+
+	snap := &snapshot{
+		mover:     mover,
+		round:     g.state.Round,
+		state:     state,
+		smState:   g.sm.state,
+		cycler:    *g.cycler,
+		hands:     make(map[t.PlayerID]Hand, len(g.Players)),
+		bids:      make(map[t.PlayerID]*Bid, len(g.Players)),
+		cardstack: append([]Card(nil), g.cardstack...),
+		plays:     append([]Play(nil), g.plays...),
+		tricks:    len(g.tricks[g.state.Round]),
+	}
+	for id, player := range g.Players {
+		snap.hands[id] = append(Hand(nil), player.Cards...)
+		snap.bids[id] = player.Bid
+	}
+	return snap
+}
+
+// Called once a move passed every check, right before it changes anything
+func (g *Game) commitMove(snap *snapshot) {
+	g.cancelUndo()
+	g.cancelClaim()
+	g.lastMove = snap
+}
+
+// Pausing is not part of a move, and the turn taken back starts over from now
+func (g *Game) restoreSnapshot(snap *snapshot, now time.Time) {
+	paused := g.state.Paused
+	*g.state = snap.state
+	g.state.Paused = paused
+	g.sm.state = snap.smState
+	*g.cycler = snap.cycler
+	for id, player := range g.Players {
+		player.Cards = snap.hands[id]
+		player.Bid = snap.bids[id]
+	}
+	g.cardstack = snap.cardstack
+	g.plays = snap.plays
+	g.tricks[snap.round] = g.tricks[snap.round][:snap.tricks]
+	g.lastMove = nil
+
+	g.resetTurnTimer(now)
+	if paused && g.state.Deadline != nil {
+		g.remaining = g.state.Deadline.Sub(now)
+		g.state.Deadline = nil
+	}
+}
+
+func (g *Game) handleUndoRequest(input t.GameInput) error {
+	if g.undo != nil {
+		return t.NewActionError(t.ErrNotAllowed, "An undo is already being voted on")
+	}
+	if g.claim != nil {
+		return t.NewActionError(t.ErrNotAllowed, "Wait for the claim to be settled")
+	}
+	snap := g.lastMove
+	if snap == nil || snap.mover != input.Player.ID {
+		return t.NewActionError(t.ErrNotAllowed, "Only your own move can be undone, before the next player acts")
+	}
+	if snap.round != g.state.Round {
+		return t.NewActionError(t.ErrNotAllowed, "A move that ended the round cannot be undone")
+	}
+
+	g.undo = newTableVote(input.Player.ID, undoWindow)
+	g.broadcast(t.MsgUndoRequested, UndoRequested{Player: g.undo.player, Deadline: g.undo.deadline})
+	return nil
+}
+
+func (g *Game) handleUndoResponse(input t.GameInput) error {
+	if g.undo == nil {
+		return t.NewActionError(t.ErrNotFound, "No undo to vote on")
+	}
+	voter, seated := g.Players[input.Player.ID]
+	if !seated {
+		return t.NewActionError(t.ErrNotSeated, "Only seated players can vote on an undo")
+	}
+	if voter.ID == g.undo.player {
+		return t.NewActionError(t.ErrNotAllowed, "You cannot vote on your own undo")
+	}
+
+	var payload UndoResponse
+	if err := json.Unmarshal(input.Env.Payload, &payload); err != nil {
+		return t.NewActionError(t.ErrInvalidPayload, "Invalid undo response")
+	}
+
+	if !payload.Approve {
+		g.resolveUndo(false, voter.PlayerName+" denied the undo")
+		return nil
+	}
+
+	g.undo.approvals[voter.ID] = true
+	if g.undo.passed(g) {
+		g.resolveUndo(true, "")
+	}
+	return nil
+}
+
+func (g *Game) resolveUndo(approved bool, reason string) {
+	player := g.undo.player
+	g.undo = nil
+
+	if approved {
+		g.restoreSnapshot(g.lastMove, time.Now())
+	}
+	g.broadcast(t.MsgUndoResolved, UndoResolved{Player: player, Approved: approved, Reason: reason})
+
+	// Clients replay events, after a rollback they need the whole picture again
+	if approved {
+		for _, p := range g.Players {
+			g.sendCardsToPlayer(p)
+		}
+		g.sendGameState(g.audienceIDs())
+	}
+}
+
+// A move by anyone else makes the pending undo moot
+func (g *Game) cancelUndo() {
+	if g.undo != nil {
+		g.resolveUndo(false, "The next player already acted")
+	}
+}
+
+func (g *Game) expireUndo(now time.Time) {
+	switch {
+	case g.undo == nil:
+	case g.undo.passed(g): // the last holdout left
+		g.resolveUndo(true, "")
+	case !now.Before(g.undo.deadline):
+		g.resolveUndo(false, "Not everyone approved in time")
+	}
+}
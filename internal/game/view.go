@@ -0,0 +1,113 @@
+package game
+
+// What a single player is allowed to see of the game
+
+import (
+	"time"
+
+	t "github.com/B33Boy/Judgement/internal/types"
+)
+
+type SeatView struct {
+	ID        t.PlayerID `json:"id"`
+	Name      string     `json:"name"`
+	CardCount int        `json:"cardCount"`
+}
+
+// Built per recipient so hidden information never leaves the server by accident
+type PlayerView struct {
+	Round      Round                  `json:"round"`
+	MaxRounds  Round                  `json:"maxRounds"`
+	State      State                  `json:"state"`
+	Dealer     t.PlayerID             `json:"dealer"`
+	TurnPlayer t.PlayerID             `json:"turnPlayer"`
+	TrumpSuit  *Suit                  `json:"trumpSuit"`
+	Seats      []SeatView             `json:"seats"` // in play order
+	Table      map[t.PlayerID]Card    `json:"table"`
+	Bids       map[t.PlayerID]Bid     `json:"bids"`
+	HandsWon   map[t.PlayerID]int     `json:"handsWon"`
+	Scores     map[t.PlayerID][]Score `json:"scores"`
+	Paused     bool                   `json:"paused"`
+	Deadline   *time.Time             `json:"deadline,omitempty"`
+	LastTrick  *Trick                 `json:"lastTrick,omitempty"` // last completed trick this round
+
+	// Only filled in for the recipient's own seat
+	Hand       Hand       `json:"hand,omitempty"`
+	LegalCards []Card     `json:"legalCards,omitempty"`
+	LegalBids  []Bid      `json:"legalBids,omitempty"`
+	BidAdvice  *BidAdvice `json:"bidAdvice,omitempty"` // coach mode only
+}
+
+func (g *Game) ViewFor(id t.PlayerID) PlayerView {
+	view := PlayerView{
+		Round:      g.state.Round,
+		MaxRounds:  g.params.maxRounds,
+		State:      g.state.State,
+		Dealer:     g.state.Dealer,
+		TurnPlayer: g.state.TurnPlayer,
+		TrumpSuit:  g.state.TrumpSuit,
+		Seats:      make([]SeatView, 0, len(g.Players)),
+		Table:      make(map[t.PlayerID]Card, len(g.state.Table)),
+		Bids:       make(map[t.PlayerID]Bid, len(g.state.Bids)),
+		HandsWon:   make(map[t.PlayerID]int, len(g.state.HandsWon)),
+		Scores:     make(map[t.PlayerID][]Score, len(g.scores)),
+		Paused:     g.state.Paused,
+		Deadline:   g.state.Deadline,
+		LastTrick:  g.lastTrick(),
+	}
+
+	for _, seat := range g.cycler.Seats() {
+		player := g.Players[seat]
+		view.Seats = append(view.Seats, SeatView{
+			ID:        seat,
+			Name:      player.PlayerName,
+			CardCount: len(player.Cards),
+		})
+	}
+	for seat, card := range g.state.Table {
+		view.Table[seat] = *card
+	}
+	for seat, bid := range g.state.Bids {
+		view.Bids[seat] = bid
+	}
+	for seat, won := range g.state.HandsWon {
+		view.HandsWon[seat] = won
+	}
+	for seat, rounds := range g.scores {
+		view.Scores[seat] = append([]Score(nil), rounds[:g.scored]...)
+	}
+
+	player, seated := g.Players[id]
+	if !seated {
+		return view // spectators only see the table
+	}
+
+	view.Hand = append(Hand(nil), player.Cards...)
+	view.LegalBids, view.LegalCards = g.legalMoves(id)
+	if view.LegalBids != nil {
+		view.BidAdvice = g.bidAdvice(id)
+	}
+	return view
+}
+
+// Moves open to the player, none unless it is their turn and the game is running
+func (g *Game) legalMoves(id t.PlayerID) (bids []Bid, cards []Card) {
+	player, seated := g.Players[id]
+	if !seated || id != g.state.TurnPlayer || g.state.Paused {
+		return nil, nil
+	}
+
+	switch g.sm.state {
+	case StateBid:
+		for bid := Bid(0); int(bid) <= len(player.Cards); bid++ {
+			bids = append(bids, bid)
+		}
+	case StatePlay:
+		for _, card := range player.Cards {
+			if g.isCardPlayable(player, card) {
+				cards = append(cards, card)
+			}
+		}
+	}
+	return bids, cards
+}
@@ -24,3 +24,20 @@ func NewServer(app *app.App) *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 }
+
+// Serves the admin routes on ADMIN_ADDR, nil when it is not set. Keep it on
+// an address that is not reachable from outside, e.g. 127.0.0.1:9090.
+func NewAdminServer(app *app.App) *http.Server {
+	addr := os.Getenv("ADMIN_ADDR")
+	if addr == "" {
+		return nil
+	}
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      app.RegisterAdminRoutes(),
+		IdleTimeout:  time.Minute,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+}
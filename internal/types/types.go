@@ -17,6 +17,8 @@ type PlayerID string
 type Player struct {
 	ID         PlayerID `json:"id"`
 	PlayerName string   `json:"playerName"`
+	Bot        bool     `json:"bot,omitempty"` // played by the server, has no connection
+	Legacy     bool     `json:"-"`             // v1 client, follows the game through state_sync only
 	Conn       *websocket.Conn
 	Send       chan Envelope
 	Ctx        context.Context
@@ -28,23 +30,66 @@ type MessageType string
 
 const (
 	// FE -> BE
-	MsgStartGame MessageType = "start_game"
-	MsgMakeBid   MessageType = "make_bid"
-	MsgPlayCard  MessageType = "play_card"
+	MsgStartGame     MessageType = "start_game"
+	MsgConfigureGame MessageType = "configure_game"
+	MsgKickPlayer    MessageType = "kick_player"
+	MsgTransferHost  MessageType = "transfer_host"
+	MsgAddBot        MessageType = "add_bot" // removed again with kick_player
+	MsgSetReady      MessageType = "set_ready"
+	MsgRematch       MessageType = "rematch"
+	MsgPauseGame     MessageType = "pause_game"
+	MsgResumeGame    MessageType = "resume_game"
+	MsgAbandonGame   MessageType = "abandon_game"
+	MsgClaimSeat     MessageType = "claim_seat"
+	MsgResume        MessageType = "resume"
+	MsgMakeBid       MessageType = "make_bid"
+	MsgPlayCard      MessageType = "play_card"
+	MsgChatSend      MessageType = "chat_send"
+	MsgMute          MessageType = "mute_player"
+	MsgEmoteSend     MessageType = "emote_send"
+
+	MsgGetTrickHistory MessageType = "get_trick_history" // answered with trick_history
+	MsgUndoRequest     MessageType = "undo_request"
+	MsgUndoResponse    MessageType = "undo_response"
+	MsgClaim           MessageType = "claim"
+	MsgClaimResponse   MessageType = "claim_response"
 
 	// BE -> FE
 	MsgWelcome       MessageType = "welcome"
 	MsgPlayersUpdate MessageType = "players_update"
+	MsgRulesUpdate   MessageType = "rules_update"
 	MsgGameStarted   MessageType = "game_started"
 	MsgGameEnd       MessageType = "game_end"
+	MsgSeriesUpdate  MessageType = "series_update"
+	MsgAbandonVote   MessageType = "abandon_vote"
+	MsgSeatClaimed   MessageType = "seat_claimed"
 	MsgPlayerHand    MessageType = "player_hand"
 	MsgStateSync     MessageType = "state_sync"
-	MsgInvalidAction MessageType = "invalid_action"
+	MsgRoundStarted  MessageType = "round_started"
+	MsgBidPlaced     MessageType = "bid_placed"
+	MsgCardPlayed    MessageType = "card_played"
+	MsgTrickWon      MessageType = "trick_won"
+	MsgRoundScored   MessageType = "round_scored"
+	MsgRoundSolved   MessageType = "round_solved"
+	MsgTurnChanged   MessageType = "turn_changed"
+	MsgTrickHistory  MessageType = "trick_history"
+	MsgUndoRequested MessageType = "undo_requested"
+	MsgUndoResolved  MessageType = "undo_resolved"
+	MsgClaimMade     MessageType = "claim_made"
+	MsgClaimResolved MessageType = "claim_resolved"
+	MsgBidAdvice     MessageType = "bid_advice" // coach mode, to the bidder only
+	MsgAck           MessageType = "ack"
+	MsgError         MessageType = "error"
+	MsgChatMessage   MessageType = "chat_message"
+	MsgChatHistory   MessageType = "chat_history"
+	MsgEmote         MessageType = "emote"
 )
 
 // ================= Transmission Types =================
 
 type Envelope struct {
+	ID      string          `json:"id,omitempty"`  // client supplied, echoed in the ack or error
+	Seq     uint64          `json:"seq,omitempty"` // per player sequence number of outbound messages
 	Type    MessageType     `json:"type"`
 	Payload json.RawMessage `json:"payload,omitempty"`
 }
@@ -58,3 +103,39 @@ type GameOutput struct {
 	Players []PlayerID
 	Env     Envelope
 }
+
+// ================= Error Types =================
+
+type ErrorCode string
+
+const (
+	ErrInvalidPayload ErrorCode = "invalid_payload"
+	ErrUnknownMessage ErrorCode = "unknown_message"
+	ErrNotAllowed     ErrorCode = "not_allowed"
+	ErrNotHost        ErrorCode = "not_host"
+	ErrNotFound       ErrorCode = "not_found"
+	ErrRateLimited    ErrorCode = "rate_limited"
+	ErrMuted          ErrorCode = "muted"
+	ErrNoGame         ErrorCode = "no_game"
+	ErrGamePaused     ErrorCode = "game_paused"
+	ErrWrongPhase     ErrorCode = "wrong_phase"
+	ErrNotSeated      ErrorCode = "not_seated"
+	ErrNotYourTurn    ErrorCode = "not_your_turn"
+	ErrMustFollowSuit ErrorCode = "must_follow_suit"
+	ErrCardNotInHand  ErrorCode = "card_not_in_hand"
+	ErrBidOutOfRange  ErrorCode = "bid_out_of_range"
+)
+
+// Rejection of a client input, sent back to the client as an error message
+type ActionError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+func NewActionError(code ErrorCode, message string) *ActionError {
+	return &ActionError{Code: code, Message: message}
+}
+
+func (e *ActionError) Error() string {
+	return string(e.Code) + ": " + e.Message
+}